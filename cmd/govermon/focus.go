@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+
+	"n4/gui-test/pkg/app"
+	"n4/gui-test/pkg/focus"
+	"n4/gui-test/pkg/graph"
+	"n4/gui-test/pkg/series"
+
+	"github.com/shirou/gopsutil/v4/process"
+	"go.uber.org/zap"
+)
+
+// Identifies process instance, PID alone may be reused
+type processKey struct {
+	pid        int32
+	createTime int64
+}
+
+type trackedProcess struct {
+	collector *series.ProcessCollector
+	graphs    []*graph.Graph
+}
+
+// Attaches graphs of the focused window process by hotkey and of processes
+// pinned in the top processes panel. Collectors are kept for the session, so
+// history is back when process is selected again
+type focusTracker struct {
+	logger    *zap.Logger
+	stats     *app.Stats
+	publisher *graphPublisher
+
+	history map[processKey]*trackedProcess
+	current *trackedProcess
+	pinned  []*trackedProcess
+}
+
+func newFocusTracker(
+	logger *zap.Logger, stats *app.Stats, publisher *graphPublisher,
+) *focusTracker {
+	return &focusTracker{
+		logger:    logger,
+		stats:     stats,
+		publisher: publisher,
+
+		history: make(map[processKey]*trackedProcess),
+	}
+}
+
+func getProcessInfo(pid int32) (processKey, string, error) {
+	proc, err := process.NewProcess(pid)
+	if err != nil {
+		return processKey{}, "", fmt.Errorf("failed to get process: %w", err)
+	}
+	createTime, err := proc.CreateTime()
+	if err != nil {
+		return processKey{}, "", fmt.Errorf("failed to get process: %w", err)
+	}
+	name, err := proc.Name()
+	if err != nil {
+		name = fmt.Sprint(pid)
+	}
+	return processKey{pid, createTime}, name, nil
+}
+
+func (t *focusTracker) getFocused() (processKey, string, error) {
+	pid, err := focus.ActivePID()
+	if err != nil {
+		return processKey{}, "", err
+	}
+	return getProcessInfo(pid)
+}
+
+// Starts collecting stats of the process, collector is reused if the process
+// was tracked before
+func (t *focusTracker) attach(key processKey, name string) *trackedProcess {
+	tracked, present := t.history[key]
+	if present {
+		tracked.collector.MarkGap()
+		t.stats.AttachProcess(tracked.collector)
+		return tracked
+	}
+	collector := t.stats.AddProcess(name, series.ProcessMatcher{
+		PID:        key.pid,
+		CreateTime: key.createTime,
+	})
+	tracked = &trackedProcess{
+		collector: collector,
+		graphs:    graph.AddedProcess(t.stats, collector),
+	}
+	t.history[key] = tracked
+	return tracked
+}
+
+// Publishes graphs of focused and pinned processes
+func (t *focusTracker) publish() {
+	var graphs []*graph.Graph
+	if t.current != nil {
+		graphs = append(graphs, t.current.graphs...)
+	}
+	for _, tracked := range t.pinned {
+		if tracked != t.current {
+			graphs = append(graphs, tracked.graphs...)
+		}
+	}
+	t.publisher.setProcesses(graphs)
+}
+
+// Attaches focused process graphs, or detaches them if already attached
+func (t *focusTracker) toggle() {
+	if t.current != nil {
+		// NOTE: Pinned process keeps collecting
+		if !slices.Contains(t.pinned, t.current) {
+			t.stats.DetachProcess(t.current.collector)
+		}
+		t.logger.Info(
+			"focused process detached",
+			zap.String("name", t.current.collector.Name),
+		)
+		t.current = nil
+		t.publish()
+		return
+	}
+
+	key, name, err := t.getFocused()
+	if err != nil {
+		t.logger.Warn("failed to track focused process", zap.Error(err))
+		return
+	}
+
+	if tracked, present := t.history[key]; present && slices.Contains(t.pinned, tracked) {
+		t.current = tracked
+	} else {
+		t.current = t.attach(key, name)
+	}
+	t.logger.Info(
+		"focused process attached",
+		zap.String("name", name),
+		zap.Int32("pid", key.pid),
+	)
+	t.publish()
+}
+
+// Pins graphs of the process, or unpins them if already pinned
+func (t *focusTracker) pin(pid int32) {
+	key, name, err := getProcessInfo(pid)
+	if err != nil {
+		t.logger.Warn("failed to pin process", zap.Error(err))
+		return
+	}
+
+	tracked, present := t.history[key]
+	if present && slices.Contains(t.pinned, tracked) {
+		t.pinned = slices.DeleteFunc(t.pinned, func(p *trackedProcess) bool {
+			return p == tracked
+		})
+		if tracked != t.current {
+			t.stats.DetachProcess(tracked.collector)
+		}
+		t.logger.Info("process unpinned", zap.String("name", name))
+		t.publish()
+		return
+	}
+
+	if !present || tracked != t.current {
+		tracked = t.attach(key, name)
+	}
+	t.pinned = append(t.pinned, tracked)
+	t.logger.Info("process pinned", zap.String("name", name), zap.Int32("pid", pid))
+	t.publish()
+}
+
+func (t *focusTracker) run(toggle <-chan struct{}, pin <-chan int32) {
+	for {
+		select {
+		case <-toggle:
+			t.toggle()
+		case pid := <-pin:
+			t.pin(pid)
+		}
+	}
+}
@@ -0,0 +1,340 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"n4/gui-test/pkg/app"
+	"n4/gui-test/pkg/config"
+	"n4/gui-test/pkg/graph"
+	"n4/gui-test/pkg/plot"
+	"n4/gui-test/pkg/series"
+
+	"github.com/dustin/go-humanize"
+	"go.uber.org/zap"
+)
+
+// Creates stats with collectors from registry, applies collection intervals,
+// adds process targets, top processes and ping targets from config
+func newStats(logger *zap.Logger, cfg *config.Config) *app.Stats {
+	stats, err := app.NewStats(
+		cfg.App.TimeRangeSeconds,
+		time.Duration(cfg.App.UpdateRateSeconds)*time.Second,
+	)
+	if err != nil {
+		logger.Fatal("failed to create stats", zap.Error(err))
+	}
+
+	for name, ms := range cfg.App.CollectorIntervalsMs {
+		err := stats.SetCollectorInterval(name, time.Duration(ms)*time.Millisecond)
+		// NOTE: Not fatal, config may be shared with other platforms
+		if errors.Is(err, app.ErrCollectorUnavailable) {
+			logger.Warn(
+				"collector interval skipped",
+				zap.String("collector", name),
+				zap.Error(err),
+			)
+			continue
+		}
+		if err != nil {
+			logger.Fatal("invalid collector interval", zap.Error(err))
+		}
+	}
+
+	names := make(map[string]struct{})
+	for _, target := range cfg.App.Processes {
+		if target.Name == "" {
+			logger.Fatal("process target name is empty")
+		}
+		if _, present := names[target.Name]; present {
+			logger.Fatal(
+				"process target name is not unique",
+				zap.String("name", target.Name),
+			)
+		}
+		names[target.Name] = struct{}{}
+
+		matcher := series.ProcessMatcher{
+			PID:     target.PID,
+			Exe:     target.Exe,
+			Cmdline: target.Cmdline,
+		}
+		if target.ExeRegex != "" {
+			re, err := regexp.Compile(target.ExeRegex)
+			if err != nil {
+				logger.Fatal(
+					"invalid process target exe regex",
+					zap.String("name", target.Name),
+					zap.Error(err),
+				)
+			}
+			matcher.ExeRegex = re
+		}
+		if matcher.IsEmpty() {
+			logger.Fatal(
+				"process target has no criteria",
+				zap.String("name", target.Name),
+			)
+		}
+
+		if target.Tree {
+			stats.AddProcessTree(target.Name, matcher, target.TopN)
+		} else {
+			stats.AddProcess(target.Name, matcher)
+		}
+	}
+
+	if cfg.App.TopProcesses.Count > 0 {
+		stats.AddTopProcesses(cfg.App.TopProcesses.Count)
+	}
+
+	addPings(logger, cfg, stats)
+	addDerived(logger, cfg, stats)
+	filterDisks(logger, cfg, stats)
+
+	return stats
+}
+
+// Adds collectors of derived entries from config
+func addDerived(logger *zap.Logger, cfg *config.Config, stats *app.Stats) {
+	names := make(map[string]struct{})
+	for _, derived := range cfg.App.Derived {
+		if derived.Name == "" {
+			logger.Fatal("derived entry name is empty")
+		}
+		if _, present := names[derived.Name]; present {
+			logger.Fatal(
+				"derived entry name is not unique",
+				zap.String("name", derived.Name),
+			)
+		}
+		names[derived.Name] = struct{}{}
+
+		expr, err := series.ParseExpr(derived.Expr)
+		if err != nil {
+			logger.Fatal(
+				"invalid derived entry expression",
+				zap.String("name", derived.Name),
+				zap.Error(err),
+			)
+		}
+		// NOTE: Not fatal, i.e. disk may be mounted later
+		for _, input := range expr.Inputs() {
+			if _, present := stats.Entry(input); !present {
+				logger.Warn(
+					"derived entry input not found",
+					zap.String("name", derived.Name),
+					zap.String("input", input),
+				)
+			}
+		}
+		stats.AddDerived(derived.Name, expr)
+	}
+}
+
+// Returns disk rules from config, exits on invalid pattern
+func newDiskRules(logger *zap.Logger, rules config.DiskRules) series.DiskRules {
+	for _, pattern := range rules.Mountpoints {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			logger.Fatal(
+				"invalid disk mountpoint pattern",
+				zap.String("pattern", pattern),
+				zap.Error(err),
+			)
+		}
+	}
+
+	ret := series.DiskRules{
+		Mountpoints: rules.Mountpoints,
+		Fstypes:     rules.Fstypes,
+	}
+	for _, expr := range rules.DeviceRegex {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			logger.Fatal(
+				"invalid disk device regex",
+				zap.String("regex", expr),
+				zap.Error(err),
+			)
+		}
+		ret.Devices = append(ret.Devices, re)
+	}
+	return ret
+}
+
+// Applies disk include and exclude rules from config
+func filterDisks(logger *zap.Logger, cfg *config.Config, stats *app.Stats) {
+	disks := app.CollectorOf[*series.DiskCollector](stats, app.CollectorDisks)
+	if disks == nil {
+		return
+	}
+	disks.SetFilter(series.DiskFilter{
+		Include: newDiskRules(logger, cfg.App.Disks.Include),
+		Exclude: newDiskRules(logger, cfg.App.Disks.Exclude),
+	})
+}
+
+// Sets aliases and free space thresholds of disk graphs from config
+func setDiskOptions(logger *zap.Logger, cfg *config.Config) {
+	options := make(map[string]graph.DiskOptions)
+	for _, mount := range cfg.App.Disks.Mounts {
+		if mount.Mountpoint == "" {
+			logger.Fatal("disk mountpoint is empty")
+		}
+		if _, present := options[mount.Mountpoint]; present {
+			logger.Fatal(
+				"disk mountpoint is not unique",
+				zap.String("mountpoint", mount.Mountpoint),
+			)
+		}
+
+		var thresholds []plot.Threshold
+		if mount.WarningFreeGiB > 0 {
+			thresholds = append(thresholds, plot.Threshold{
+				Value: mount.WarningFreeGiB * humanize.GiByte,
+				Level: plot.ThresholdWarning,
+				Below: true,
+			})
+		}
+		if mount.CriticalFreeGiB > 0 {
+			thresholds = append(thresholds, plot.Threshold{
+				Value: mount.CriticalFreeGiB * humanize.GiByte,
+				Level: plot.ThresholdCritical,
+				Below: true,
+			})
+		}
+		options[mount.Mountpoint] = graph.DiskOptions{
+			Alias:      mount.Alias,
+			Thresholds: thresholds,
+		}
+	}
+	graph.SetDiskOptions(options)
+}
+
+// Adds latency collectors of ping targets from config
+func addPings(logger *zap.Logger, cfg *config.Config, stats *app.Stats) {
+	names := make(map[string]struct{})
+	for _, target := range cfg.App.Ping {
+		if target.Name == "" {
+			logger.Fatal("ping target name is empty")
+		}
+		if _, present := names[target.Name]; present {
+			logger.Fatal(
+				"ping target name is not unique",
+				zap.String("name", target.Name),
+			)
+		}
+		names[target.Name] = struct{}{}
+
+		if target.Address == "" {
+			logger.Fatal(
+				"ping target address is empty",
+				zap.String("name", target.Name),
+			)
+		}
+		probe, err := series.ParsePingProbe(target.Probe)
+		if err != nil {
+			logger.Fatal(
+				"invalid ping target probe",
+				zap.String("name", target.Name),
+				zap.Error(err),
+			)
+		}
+
+		timeout := time.Duration(target.TimeoutMs) * time.Millisecond
+		stats.AddPing(target.Name, probe, target.Address, timeout)
+	}
+}
+
+// Creates graphs and applies enabled state from config. Settings of new
+// graphs are added to config
+func newGraphs(logger *zap.Logger, cfg *config.Config, stats *app.Stats) graph.Collection {
+	setDiskOptions(logger, cfg)
+	graphs := graph.Collection(graph.All(stats))
+	applyGraphSettings(logger, cfg, graphs)
+	return graphs
+}
+
+// Applies enabled state and overlays from config to graphs, settings of
+// graphs missing in config are added to it
+func applyGraphSettings(logger *zap.Logger, cfg *config.Config, graphs []*graph.Graph) {
+	for _, graph := range graphs {
+		settingName := graph.GetName()
+		if settingName == "" {
+			logger.Fatal(
+				"graph settings name is empty",
+				zap.String("graph", graph.NameLabel),
+			)
+		}
+		settings, present := cfg.App.GraphSettings[settingName]
+		if present {
+			graph.SetActive(settings.Enabled)
+			graph.SetOverlays(newOverlays(settings.Overlays))
+		} else {
+			cfg.App.GraphSettings[settingName] = &config.GraphSettings{
+				Enabled: graph.IsActive(),
+			}
+		}
+	}
+}
+
+func newOverlays(overlays config.GraphOverlays) graph.Overlays {
+	return graph.Overlays{
+		Mean:   overlays.Mean,
+		P95:    overlays.P95,
+		MinMax: overlays.MinMax,
+		Window: time.Duration(overlays.WindowSeconds) * time.Second,
+	}
+}
+
+// Returns update adding graphs of plugged in disks and removing graphs of
+// released ones, false if nothing changed. NOTE: Config is changed by update
+// only, so it's not written off the UI goroutine
+func syncDiskGraphs(
+	logger *zap.Logger, cfg *config.Config, stats *app.Stats, graphs graph.Collection,
+) (graph.Update, bool) {
+	added, removed := graph.SyncDisks(stats, graphs)
+	if len(added) == 0 && len(removed) == 0 {
+		return graph.Update{Graphs: graphs}, false
+	}
+	for _, gr := range added {
+		logger.Info("disk found", zap.String("graph", gr.GetName()))
+	}
+	for _, gr := range removed {
+		logger.Info("disk released", zap.String("graph", gr.GetName()))
+	}
+
+	return graph.Update{
+		Graphs: graphs.Remove(removed...).Add(added...),
+		Prepare: func() {
+			applyGraphSettings(logger, cfg, added)
+			cfg.Save()
+		},
+		Removed: removed,
+	}, true
+}
+
+// Publishes graphs of disks plugged in or released until stop is closed
+func runDiskSync(
+	logger *zap.Logger,
+	cfg *config.Config,
+	stats *app.Stats,
+	publisher *graphPublisher,
+	stop <-chan struct{},
+) {
+	ticker := time.NewTicker(series.DiskDiscoverInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			update, changed := syncDiskGraphs(logger, cfg, stats, publisher.getBase())
+			if changed {
+				publisher.setBase(update)
+			}
+		}
+	}
+}
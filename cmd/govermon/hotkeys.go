@@ -27,12 +27,15 @@ func registerHotkeys(useDebugSet bool) {
 
 	var hkPassthru *hotkey.Hotkey
 	var hkExit *hotkey.Hotkey
+	var hkFocused *hotkey.Hotkey
 	if useDebugSet {
 		hkPassthru = hotkey.New(hotkeyTogglePassthroughDebug.mods, hotkeyTogglePassthroughDebug.key)
 		hkExit = hotkey.New(hotkeyExitDebug.mods, hotkeyExitDebug.key)
+		hkFocused = hotkey.New(hotkeyTrackFocusedDebug.mods, hotkeyTrackFocusedDebug.key)
 	} else {
 		hkPassthru = hotkey.New(hotkeyTogglePassthrough.mods, hotkeyTogglePassthrough.key)
 		hkExit = hotkey.New(hotkeyExit.mods, hotkeyExit.key)
+		hkFocused = hotkey.New(hotkeyTrackFocused.mods, hotkeyTrackFocused.key)
 	}
 
 	hkeys := []hotkeyData{
@@ -54,6 +57,15 @@ func registerHotkeys(useDebugSet bool) {
 				}
 			},
 		},
+		{
+			"TrackFocused", hkFocused,
+			func(hk *hotkey.Hotkey, logger *zap.Logger) {
+				for range hk.Keyup() {
+					logger.Info("hotkey event", zap.String("event_name", "Keyup"))
+					trackFocused <- struct{}{}
+				}
+			},
+		},
 	}
 
 	for _, hkData := range hkeys {
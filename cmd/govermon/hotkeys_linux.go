@@ -0,0 +1,31 @@
+package main
+
+import "golang.design/x/hotkey"
+
+// TODO: use config for hotkeys
+
+// NOTE: Mod4 is Super(Win) key, Mod1 is Alt
+
+var hotkeyTogglePassthrough = hotkeyDefinition{
+	[]hotkey.Modifier{hotkey.Mod4, hotkey.ModShift}, hotkey.KeyO,
+}
+
+var hotkeyTogglePassthroughDebug = hotkeyDefinition{
+	[]hotkey.Modifier{hotkey.Mod4, hotkey.ModShift, hotkey.Mod1}, hotkey.KeyO,
+}
+
+var hotkeyExit = hotkeyDefinition{
+	[]hotkey.Modifier{hotkey.Mod4, hotkey.ModShift}, hotkey.KeyI,
+}
+
+var hotkeyExitDebug = hotkeyDefinition{
+	[]hotkey.Modifier{hotkey.Mod4, hotkey.ModShift, hotkey.Mod1}, hotkey.KeyI,
+}
+
+var hotkeyTrackFocused = hotkeyDefinition{
+	[]hotkey.Modifier{hotkey.Mod4, hotkey.ModShift}, hotkey.KeyP,
+}
+
+var hotkeyTrackFocusedDebug = hotkeyDefinition{
+	[]hotkey.Modifier{hotkey.Mod4, hotkey.ModShift, hotkey.Mod1}, hotkey.KeyP,
+}
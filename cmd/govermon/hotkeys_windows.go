@@ -19,3 +19,11 @@ var hotkeyExit = hotkeyDefinition{
 var hotkeyExitDebug = hotkeyDefinition{
 	[]hotkey.Modifier{hotkey.ModWin, hotkey.ModShift, hotkey.ModAlt}, hotkey.KeyI,
 }
+
+var hotkeyTrackFocused = hotkeyDefinition{
+	[]hotkey.Modifier{hotkey.ModWin, hotkey.ModShift}, hotkey.KeyP,
+}
+
+var hotkeyTrackFocusedDebug = hotkeyDefinition{
+	[]hotkey.Modifier{hotkey.ModWin, hotkey.ModShift, hotkey.ModAlt}, hotkey.KeyP,
+}
@@ -1,16 +1,13 @@
 package main
 
 import (
-	"context"
 	"fmt"
 	"log"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
 	"os/signal"
-	"slices"
 	"syscall"
-	"time"
 
 	"n4/gui-test/pkg/app"
 	"n4/gui-test/pkg/backend/ebiten"
@@ -28,7 +25,12 @@ const (
 var (
 	exit              = make(chan struct{})
 	togglePassthrough = make(chan struct{})
+	trackFocused      = make(chan struct{})
+	graphsUpdates     = make(chan graph.Update)
 	stopUpdates       = make(chan struct{})
+
+	// NOTE: Buffered, so overlay doesn't wait for graphs to be built
+	pinProcess = make(chan int32, 8)
 )
 
 type flagVars struct {
@@ -92,28 +94,25 @@ func handleExit() {
 	}()
 }
 
-func handleUpdates(logger *zap.Logger, rateSeconds int, cb func()) {
+func handleUpdates(logger *zap.Logger, stats *app.Stats) {
 	go func() {
-		for {
-			ctx, cancel := context.WithTimeout(
-				context.Background(), time.Duration(rateSeconds)*time.Second,
-			)
-			defer cancel()
-
-			select {
-			case <-stopUpdates:
-				logger.Info("We're done here")
-				return
-			// case <-time.After(rateSeconds * time.Second):
-			case <-ctx.Done():
-				cb()
-				cancel()
-			}
-		}
+		stats.Run(stopUpdates)
+		logger.Info("We're done here")
 	}()
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "snapshot":
+			runSnapshot(os.Args[2:])
+			return
+		case "tui":
+			runTUI(os.Args[2:])
+			return
+		}
+	}
+
 	fVars := handleFlags()
 
 	logger, err := zap.NewProduction()
@@ -141,69 +140,22 @@ func main() {
 
 	go registerHotkeys(fVars.useDebugHotkeys)
 
-	stats := app.NewStats(cfg.App.TimeRangeSeconds)
-	// TODO: Is there a better solution for collectors of dynamic instances
-	stats.Disks.Discover() // NOTE: Prefetch available disks to use it in graph init
+	stats := newStats(logger, cfg)
 
 	handleExit()
 	handleSignals(logger)
-	handleUpdates(logger, cfg.App.UpdateRateSeconds, stats.Update)
-
-	graphs := graph.Collection{
-		graph.SelfUpdate(stats),
-		graph.SelfFramerate(stats),
-		graph.SelfRuntimeMemAlloc(stats),
-		graph.SelfRuntimeMemSys(stats),
-
-		graph.SelfMemRSS(stats),
-		graph.SelfMemVMS(stats),
-		graph.SelfMemHWM(stats),
-		graph.SelfMemData(stats),
-		graph.SelfMemStack(stats),
-		graph.SelfMemLocked(stats),
-		graph.SelfMemSwap(stats),
-
-		graph.SelfCPUSys(stats),
-		graph.SelfCPUUser(stats),
-		graph.SelfCPUIdle(stats),
-		graph.SelfCPUIowait(stats),
-		graph.SelfCPUSteal(stats),
-		graph.SelfCPUIrq(stats),
-		graph.SelfCPUGuest(stats),
-		graph.SelfCPUSoftirq(stats),
-		graph.SelfCPUNice(stats),
-		graph.SelfCPUGuestNice(stats),
-
-		graph.SelfCPUPerc(stats),
-
-		graph.SysMemCommit(stats),
-		graph.SysMemAvailable(stats),
-		graph.SysMemUsed(stats),
-		graph.SysMemUsedPercent(stats),
-	}
+	handleUpdates(logger, stats)
 
-	for _, graph := range graphs {
-		settingName := graph.GetName()
-		if settingName == "" {
-			logger.Fatal(
-				"graph settings name is empty",
-				zap.String("graph", graph.NameLabel),
-			)
-		}
-		settings, present := cfg.App.GraphSettings[settingName]
-		if present {
-			graph.SetActive(settings.Enabled)
-		} else {
-			cfg.App.GraphSettings[settingName] = &config.GraphSettings{
-				Enabled: graph.IsActive(),
-			}
-		}
-	}
+	graphs := newGraphs(logger, cfg, stats)
 	cfg.Save()
 
-	graphs = slices.Concat(graphs, graph.Disks(stats))
+	publisher := newGraphPublisher(graphs, graphsUpdates)
+	go newFocusTracker(logger, stats, publisher).run(trackFocused, pinProcess)
+	go runDiskSync(logger, cfg, stats, publisher, stopUpdates)
 
-	ebiten.Window(cfg, graphs, stats, togglePassthrough, exit)
+	ebiten.Window(
+		cfg, graphs, stats, togglePassthrough, exit, graphsUpdates, pinProcess,
+	)
 
 	logger.Info("Aloha!")
 }
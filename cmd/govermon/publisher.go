@@ -0,0 +1,51 @@
+package main
+
+import (
+	"slices"
+	"sync"
+
+	"n4/gui-test/pkg/graph"
+)
+
+// Sends graphs to UI composed of base graphs, changed by disk sync, and
+// graphs of tracked processes. Safe to use from multiple goroutines
+type graphPublisher struct {
+	lock sync.Mutex
+
+	base      graph.Collection
+	processes graph.Collection
+
+	updates chan<- graph.Update
+}
+
+func newGraphPublisher(base graph.Collection, updates chan<- graph.Update) *graphPublisher {
+	return &graphPublisher{base: base, updates: updates}
+}
+
+func (p *graphPublisher) getBase() graph.Collection {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.base
+}
+
+// NOTE: Must be called with lock held, so updates are sent in order
+func (p *graphPublisher) send(update graph.Update) {
+	update.Graphs = slices.Concat(update.Graphs, p.processes)
+	p.updates <- update
+}
+
+// Replaces base graphs with graphs of update and sends it
+func (p *graphPublisher) setBase(update graph.Update) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.base = update.Graphs
+	p.send(update)
+}
+
+// Replaces graphs of tracked processes and sends them after base graphs
+func (p *graphPublisher) setProcesses(graphs []*graph.Graph) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.processes = graphs
+	p.send(graph.Update{Graphs: p.base})
+}
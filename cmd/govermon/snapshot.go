@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"n4/gui-test/pkg/backend/raster"
+
+	"github.com/spf13/pflag"
+	"go.uber.org/zap"
+)
+
+type snapshotFlagVars struct {
+	cfgPath  string
+	output   string
+	duration time.Duration
+}
+
+func handleSnapshotFlags(args []string) (fVars snapshotFlagVars) {
+	flagSet := pflag.NewFlagSet("snapshot", pflag.ExitOnError)
+	flagSet.Usage = func() {
+		fmt.Println("Usage: " + appName + " snapshot [flags]")
+		fmt.Println(flagSet.FlagUsages())
+		os.Exit(0)
+	}
+	flagSet.StringVarP(
+		&fVars.cfgPath, "config", "c", "",
+		"path to .yaml config file (default is the same as for overlay)",
+	)
+	flagSet.StringVarP(
+		&fVars.output, "output", "o", appName+".png",
+		"path to output .png file",
+	)
+	flagSet.DurationVarP(
+		&fVars.duration, "duration", "d", 5*time.Second,
+		"time to collect stats before rendering",
+	)
+	flagSet.Parse(args)
+	return fVars
+}
+
+// Collects stats for a while and renders graphs into a PNG file
+func runSnapshot(args []string) {
+	fVars := handleSnapshotFlags(args)
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("can't initialize zap logger: %v", err)
+	}
+	defer logger.Sync()
+
+	cfg := loadConfig(logger, fVars.cfgPath)
+
+	stats := newStats(logger, cfg)
+
+	graphs := newGraphs(logger, cfg, stats)
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		stats.Run(stop)
+		close(done)
+	}()
+	time.Sleep(fVars.duration)
+	close(stop)
+	<-done
+
+	renderer, err := raster.NewRenderer(logger, cfg)
+	if err != nil {
+		logger.Fatal("failed to create renderer", zap.Error(err))
+	}
+
+	out, err := os.Create(fVars.output)
+	if err != nil {
+		logger.Fatal("failed to create snapshot file", zap.Error(err))
+	}
+	defer out.Close()
+
+	err = renderer.RenderPNG(out, graphs.NewPlotWidgets()...)
+	if err != nil {
+		logger.Fatal("failed to render snapshot", zap.Error(err))
+	}
+	logger.Info("Snapshot saved", zap.String("path", fVars.output))
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"n4/gui-test/pkg/backend/tui"
+	"n4/gui-test/pkg/graph"
+
+	"github.com/spf13/pflag"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type tuiFlagVars struct {
+	cfgPath string
+	mode    string
+	logPath string
+}
+
+func handleTUIFlags(args []string) (fVars tuiFlagVars) {
+	flagSet := pflag.NewFlagSet("tui", pflag.ExitOnError)
+	flagSet.Usage = func() {
+		fmt.Println("Usage: " + appName + " tui [flags]")
+		fmt.Println(flagSet.FlagUsages())
+		os.Exit(0)
+	}
+	flagSet.StringVarP(
+		&fVars.cfgPath, "config", "c", "",
+		"path to .yaml config file (default is the same as for overlay)",
+	)
+	flagSet.StringVarP(
+		&fVars.mode, "mode", "m", tui.ModeBraille.String(),
+		"plot mode: braille or sparkline",
+	)
+	flagSet.StringVar(
+		&fVars.logPath, "log", "",
+		"file to write logs to while terminal is open (default discards them)",
+	)
+	flagSet.Parse(args)
+	return fVars
+}
+
+// Returns settings panel with a page of graphs that can be toggled, keys are
+// assigned within the page. Page is wrapped around the number of pages
+func tuiSettingsPanel(graphs graph.Collection, page int) (panel tui.SettingsPanel, toggles map[byte]*graph.Graph) {
+	pageSize := len(tui.SettingsKeys)
+	panel.Pages = max((len(graphs)+pageSize-1)/pageSize, 1)
+	panel.Page = (page%panel.Pages + panel.Pages) % panel.Pages
+
+	toggles = make(map[byte]*graph.Graph)
+	start := panel.Page * pageSize
+	for idx, gr := range graphs[start:min(start+pageSize, len(graphs))] {
+		key := tui.SettingsKeys[idx]
+		toggles[key] = gr
+		panel.Items = append(panel.Items, tui.SettingsItem{
+			Key:         rune(key),
+			Label:       gr.NameLabel,
+			Description: gr.Description,
+			Active:      gr.IsActive(),
+		})
+	}
+	return panel, toggles
+}
+
+// Log output that is switched away from stderr while terminal is open, so
+// logs don't draw over the screen
+type logSink struct {
+	lock sync.Mutex
+	out  zapcore.WriteSyncer
+}
+
+func (s *logSink) Write(p []byte) (int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.out.Write(p)
+}
+
+func (s *logSink) Sync() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.out.Sync()
+}
+
+func (s *logSink) set(out zapcore.WriteSyncer) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.out = out
+}
+
+// Returns production logger writing to sink
+func newSinkLogger(sink *logSink) *zap.Logger {
+	core := zapcore.NewCore(
+		zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+		sink,
+		zap.InfoLevel,
+	)
+	return zap.New(core, zap.AddCaller(), zap.AddStacktrace(zap.ErrorLevel))
+}
+
+// Returns log output used while terminal is open, logs are discarded if
+// path is empty
+func openTUILog(path string) (zapcore.WriteSyncer, func() error, error) {
+	if path == "" {
+		return zapcore.AddSync(io.Discard), func() error { return nil }, nil
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+	return zapcore.Lock(file), file.Close, nil
+}
+
+// Shows graphs in terminal until q or Ctrl+C is pressed
+func runTUI(args []string) {
+	fVars := handleTUIFlags(args)
+
+	stderr := zapcore.Lock(os.Stderr)
+	sink := &logSink{out: stderr}
+	logger := newSinkLogger(sink)
+	defer logger.Sync()
+
+	cfg := loadConfig(logger, fVars.cfgPath)
+
+	mode, err := tui.ParseMode(fVars.mode)
+	if err != nil {
+		logger.Fatal("invalid flag", zap.Error(err))
+	}
+	renderer := tui.NewRenderer(logger, cfg, mode, tui.DetectColorMode())
+
+	stats := newStats(logger, cfg)
+
+	graphs := newGraphs(logger, cfg, stats)
+	cfg.Save()
+
+	logOut, closeLog, err := openTUILog(fVars.logPath)
+	if err != nil {
+		logger.Fatal("invalid flag", zap.Error(err))
+	}
+	defer closeLog()
+
+	term, err := tui.OpenTerminal()
+	if err != nil {
+		logger.Fatal("failed to open terminal", zap.Error(err))
+	}
+	sink.set(logOut)
+
+	settingsShown := false
+	settingsPage := 0
+	draw := func() error {
+		width, height, err := term.Size()
+		if err != nil {
+			return err
+		}
+		var panel tui.SettingsPanel
+		if settingsShown {
+			// NOTE: Page is kept in range as disks come and go
+			panel, _ = tuiSettingsPanel(graphs, settingsPage)
+			settingsPage = panel.Page
+		}
+		return term.Draw(
+			renderer.Render(width, panel, graphs.NewPlotWidgets()...), height,
+		)
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go stats.Run(stop)
+
+	// NOTE: Redraws at the base update rate, collectors run on their own
+	ticker := time.NewTicker(time.Duration(cfg.App.UpdateRateSeconds) * time.Second)
+	defer ticker.Stop()
+	keys := term.Keys()
+
+	err = draw()
+loop:
+	for err == nil {
+		select {
+		case <-ticker.C:
+			if update, changed := syncDiskGraphs(logger, cfg, stats, graphs); changed {
+				graphs = update.Apply()
+			}
+		case key, ok := <-keys:
+			if !ok {
+				break loop
+			}
+			switch {
+			case key == 'q' || key == tui.KeyCtrlC:
+				break loop
+			case key == 's':
+				settingsShown = !settingsShown
+			case key == 'm':
+				renderer.Mode = renderer.Mode.Next()
+			case settingsShown && key == tui.KeyPrevPage:
+				settingsPage--
+			case settingsShown && key == tui.KeyNextPage:
+				settingsPage++
+			case settingsShown && strings.IndexByte(tui.SettingsKeys, key) >= 0:
+				_, toggles := tuiSettingsPanel(graphs, settingsPage)
+				gr, present := toggles[key]
+				if !present {
+					break
+				}
+				grCfg, present := cfg.App.GraphSettings[gr.GetName()]
+				if !present {
+					break
+				}
+				grCfg.Enabled = !gr.IsActive()
+				gr.SetActive(grCfg.Enabled)
+				cfg.Save()
+			}
+		}
+		err = draw()
+	}
+
+	closeErr := term.Close()
+	logger.Sync()
+	sink.set(stderr)
+	if err != nil {
+		logger.Fatal("failed to draw", zap.Error(err))
+	}
+	if closeErr != nil {
+		logger.Error("failed to restore terminal", zap.Error(closeErr))
+	}
+}
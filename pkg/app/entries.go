@@ -0,0 +1,51 @@
+package app
+
+import (
+	"maps"
+
+	"n4/gui-test/pkg/series"
+)
+
+// Returns entries of stats by name, the names derived entries refer to.
+// Names are prefixed with collector name, i.e. sys_mem_used, entries of
+// disks, processes and pings with their names, i.e. disk___free for root
+// disk. Health of each collector, as numbers of CollectorHealth, and its error
+// count are under health_ prefix, i.e. health_disk_state and
+// health_disk_errors. NOTE: Derived entries can't be referred to
+func (s *Stats) Entries() map[string]*series.Entry {
+	entries := series.GetEntries(s)
+	for _, c := range s.collectors {
+		instances := c.instances()
+		// NOTE: Reversed, so the first instance with a name wins
+		for x := len(instances) - 1; x >= 0; x-- {
+			switch {
+			case c.entries != nil:
+				maps.Copy(entries, c.entries(instances[x]))
+			case !c.info.Dynamic:
+				maps.Copy(entries, series.EntriesOf(instances[x], c.info.Name))
+			}
+		}
+	}
+
+	for _, c := range s.collectors {
+		prefix := "health_" + c.info.Name
+		entries[prefix+"_state"] = &c.health
+		entries[prefix+"_errors"] = &c.errors
+	}
+
+	return entries
+}
+
+// Returns entry by name from Entries, false if there is no such entry
+func (s *Stats) Entry(name string) (*series.Entry, bool) {
+	entry, present := s.Entries()[name]
+	return entry, present
+}
+
+// Adds collector of expression over entries of stats. Inputs are resolved
+// and subscribed when its value gets active
+func (s *Stats) AddDerived(name string, expr *series.Expr) *series.DerivedCollector {
+	derived := series.NewDerivedCollector(name, expr, s.Entry, s.size)
+	s.derived = append(s.derived, derived)
+	return derived
+}
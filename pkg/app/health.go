@@ -0,0 +1,120 @@
+package app
+
+import (
+	"errors"
+
+	"n4/gui-test/pkg/series"
+)
+
+// Consecutive failed collections after which collector is failing
+const failingAfter = 3
+
+type CollectorHealth int
+
+const (
+	// The latest collection succeeded
+	HealthOK CollectorHealth = iota
+	// The latest collection failed partially or collector started failing
+	HealthDegraded
+	// Collector failed failingAfter times in a row
+	HealthFailing
+)
+
+func (h CollectorHealth) String() string {
+	switch h {
+	case HealthOK:
+		return "ok"
+	case HealthDegraded:
+		return "degraded"
+	case HealthFailing:
+		return "failing"
+	}
+	return "unknown"
+}
+
+// Health of collector at the time of the latest collection
+type CollectorStatus struct {
+	Name   string
+	Health CollectorHealth
+	// Message of the latest error, empty while collector is ok
+	LastError string
+	// Errors since start
+	Errors int
+
+	// Health value, as numbers of CollectorHealth, and error count history
+	HealthEntry *series.Entry
+	ErrorsEntry *series.Entry
+}
+
+// Records result of collection. Errors other than PartialError count as
+// failures, failing collector backs off
+func (c *statsCollector) report(err error) {
+	c.statusLock.Lock()
+	defer c.statusLock.Unlock()
+
+	var partial *series.PartialError
+	switch {
+	case err == nil:
+		c.failures = 0
+		c.healthState = HealthOK
+	case errors.As(err, &partial):
+		c.failures = 0
+		c.healthState = HealthDegraded
+	default:
+		c.failures++
+		c.healthState = HealthDegraded
+		if c.failures >= failingAfter {
+			c.healthState = HealthFailing
+		}
+	}
+	c.lastErr = err
+	if err != nil {
+		c.errCount++
+	}
+
+	c.health.AddValue(float64(c.healthState))
+	c.errors.AddValue(float64(c.errCount))
+}
+
+func (c *statsCollector) getFailures() int {
+	c.statusLock.Lock()
+	defer c.statusLock.Unlock()
+	return c.failures
+}
+
+func (c *statsCollector) getStatus() CollectorStatus {
+	c.statusLock.Lock()
+	defer c.statusLock.Unlock()
+
+	status := CollectorStatus{
+		Name:   c.info.Name,
+		Health: c.healthState,
+		Errors: c.errCount,
+
+		HealthEntry: &c.health,
+		ErrorsEntry: &c.errors,
+	}
+	if c.lastErr != nil {
+		status.LastError = c.lastErr.Error()
+	}
+	return status
+}
+
+// Returns health of collector registered under name, false if it's not
+// created by stats
+func (s *Stats) CollectorStatus(name string) (CollectorStatus, bool) {
+	c := s.getCollector(name)
+	if c == nil {
+		return CollectorStatus{}, false
+	}
+	return c.getStatus(), true
+}
+
+// Returns health of collectors, in collection order
+func (s *Stats) CollectorStatuses() []CollectorStatus {
+	statuses := make([]CollectorStatus, len(s.collectors))
+	for x, c := range s.collectors {
+		statuses[x] = c.getStatus()
+	}
+	return statuses
+}
@@ -0,0 +1,46 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"n4/gui-test/pkg/series"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatsCollector_report(t *testing.T) {
+	s := newTestStats(t, fakeRegistration("flaky", 0))
+	c := getTestCollector(t, s, "flaky")
+	health := c.health.Subscribe(&series.Subscriber{})
+
+	failed := errors.New("failed")
+	partial := &series.PartialError{Err: errors.New("one disk")}
+	steps := []struct {
+		err      error
+		want     CollectorHealth
+		failures int
+	}{
+		{nil, HealthOK, 0},
+		{partial, HealthDegraded, 0},
+		{failed, HealthDegraded, 1},
+		{failed, HealthDegraded, 2},
+		{failed, HealthFailing, 3},
+		{failed, HealthFailing, 4},
+		{nil, HealthOK, 0},
+	}
+	for x, step := range steps {
+		c.report(step.err)
+		status := c.getStatus()
+		assert.Equal(t, step.want, status.Health, "step %d", x)
+		assert.Equal(t, step.failures, c.getFailures(), "step %d", x)
+		assert.Equal(t, float64(step.want), health.GetFirstValue(), "step %d", x)
+	}
+
+	status := c.getStatus()
+	assert.Equal(t, 5, status.Errors)
+	assert.Empty(t, status.LastError)
+
+	c.report(failed)
+	assert.Equal(t, "failed", c.getStatus().LastError)
+}
@@ -0,0 +1,239 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"runtime"
+	"slices"
+	"time"
+
+	"n4/gui-test/pkg/series"
+)
+
+// Names of built-in collectors, graphs are registered under them too
+const (
+	CollectorSelf         = "self"
+	CollectorSelfCPU      = "self_cpu"
+	CollectorSelfMem      = "self_mem"
+	CollectorSysMem       = "sys_mem"
+	CollectorSwap         = "swap"
+	CollectorSysMemEx     = "sys_mem_ex"
+	CollectorPSI          = "psi"
+	CollectorSensors      = "sensors"
+	CollectorPower        = "power"
+	CollectorDisks        = "disk"
+	CollectorProcesses    = "processes"
+	CollectorProcessTrees = "process_trees"
+	CollectorTopProcesses = "top_processes"
+	CollectorPings        = "pings"
+	CollectorDerived      = "derived"
+)
+
+// Collector is not registered, not supported on this platform or has nothing
+// to collect
+var ErrCollectorUnavailable = errors.New("collector is not available")
+
+// Collector metadata kept in registry
+type CollectorInfo struct {
+	// Unique name, graphs of the collector are registered under it
+	Name string
+	// GOOS values the collector is available on, empty for all
+	Platforms []string
+	// Default collection interval, zero for the interval of stats
+	Interval time.Duration
+	// Collection taking longer is recorded as a gap, zero for the default
+	Timeout time.Duration
+	// Dynamic collectors have instances added while running, i.e. process
+	// targets. Static ones are created with stats
+	Dynamic bool
+}
+
+// Returns true if collector is available on the current platform
+func (i CollectorInfo) IsSupported() bool {
+	return len(i.Platforms) == 0 || slices.Contains(i.Platforms, runtime.GOOS)
+}
+
+type CollectorRegistration struct {
+	CollectorInfo
+
+	// Creates static collector, it's looked up by name with CollectorOf.
+	// Returning nil skips the collector, i.e. when it has nothing to collect
+	New func(s *Stats) series.ICollector
+	// Returns current instances of dynamic collector
+	Instances func(s *Stats) []series.ICollector
+	// Returns entries of instance by name for Stats.Entries. Entries of
+	// static collectors are prefixed with collector name if it's nil,
+	// dynamic ones have none
+	Entries func(instance series.ICollector) map[string]*series.Entry
+}
+
+var registry []CollectorRegistration
+
+// Registers collector created by every new Stats. Supposed to be called from
+// init, panics on invalid or duplicate registration
+func RegisterCollector(reg CollectorRegistration) {
+	if reg.Name == "" {
+		panic("collector name must not be empty")
+	}
+	if reg.Dynamic && reg.Instances == nil || !reg.Dynamic && reg.New == nil {
+		panic("collector must have New if static or Instances if dynamic: " + reg.Name)
+	}
+	for _, known := range registry {
+		if known.Name == reg.Name {
+			panic(fmt.Sprintf("collector %q is already registered", reg.Name))
+		}
+	}
+	registry = append(registry, reg)
+}
+
+// Returns registrations with static collectors first, in registration order
+// otherwise
+func registered() []CollectorRegistration {
+	ordered := make([]CollectorRegistration, 0, len(registry))
+	for _, dynamic := range []bool{false, true} {
+		for _, reg := range registry {
+			if reg.Dynamic == dynamic {
+				ordered = append(ordered, reg)
+			}
+		}
+	}
+	return ordered
+}
+
+// Returns metadata of registered collectors in collection order
+func Collectors() []CollectorInfo {
+	regs := registered()
+	infos := make([]CollectorInfo, len(regs))
+	for x, reg := range regs {
+		infos[x] = reg.CollectorInfo
+	}
+	return infos
+}
+
+func init() {
+	for _, reg := range []CollectorRegistration{
+		{
+			CollectorInfo: CollectorInfo{Name: CollectorSelf},
+			New: func(s *Stats) series.ICollector {
+				return &selfCollector{s}
+			},
+		},
+		{
+			CollectorInfo: CollectorInfo{Name: CollectorSelfMem},
+			New: func(s *Stats) series.ICollector {
+				return series.NewProcessMemCollector(s.self, s.size)
+			},
+		},
+		{
+			CollectorInfo: CollectorInfo{
+				Name:     CollectorSelfCPU,
+				Interval: 250 * time.Millisecond,
+			},
+			New: func(s *Stats) series.ICollector {
+				return series.NewProcessCPUCollector(s.self, s.size)
+			},
+		},
+		{
+			CollectorInfo: CollectorInfo{Name: CollectorSysMem},
+			New: func(s *Stats) series.ICollector {
+				return series.NewSysMemCollector(s.size)
+			},
+		},
+		{
+			CollectorInfo: CollectorInfo{Name: CollectorSwap},
+			New: func(s *Stats) series.ICollector {
+				return series.NewSwapCollector(s.size)
+			},
+		},
+		{
+			CollectorInfo: CollectorInfo{
+				Name:      CollectorPSI,
+				Platforms: []string{"linux"},
+			},
+			New: func(s *Stats) series.ICollector {
+				return series.NewPSICollector(s.size)
+			},
+		},
+		{
+			CollectorInfo: CollectorInfo{
+				Name:     CollectorDisks,
+				Interval: 10 * time.Second,
+			},
+			New: func(s *Stats) series.ICollector {
+				disks := series.NewDiskCollector(series.ListPartitions, s.size)
+				// NOTE: Prefetch available disks to use them in graph init
+				_ = disks.Discover()
+				return disks
+			},
+			Entries: func(instance series.ICollector) map[string]*series.Entry {
+				entries := make(map[string]*series.Entry)
+				for _, disk := range instance.(*series.DiskCollector).GetDisks() {
+					maps.Copy(entries, series.EntriesOf(disk, "disk", series.NamePart(disk.Name)))
+				}
+				return entries
+			},
+		},
+		{
+			CollectorInfo: CollectorInfo{Name: CollectorProcesses, Dynamic: true},
+			Instances: func(s *Stats) []series.ICollector {
+				s.procLock.Lock()
+				defer s.procLock.Unlock()
+				return toCollectors(s.processes)
+			},
+			Entries: func(instance series.ICollector) map[string]*series.Entry {
+				proc := instance.(*series.ProcessCollector)
+				prefix := "proc_" + series.NamePart(proc.Name)
+				entries := series.EntriesOf(proc.CPU, prefix, "cpu")
+				maps.Copy(entries, series.EntriesOf(proc.Mem, prefix, "mem"))
+				return entries
+			},
+		},
+		{
+			CollectorInfo: CollectorInfo{Name: CollectorProcessTrees, Dynamic: true},
+			Instances: func(s *Stats) []series.ICollector {
+				s.procLock.Lock()
+				defer s.procLock.Unlock()
+				return toCollectors(s.processTrees)
+			},
+		},
+		{
+			CollectorInfo: CollectorInfo{Name: CollectorTopProcesses, Dynamic: true},
+			Instances: func(s *Stats) []series.ICollector {
+				s.procLock.Lock()
+				defer s.procLock.Unlock()
+				if s.topProcesses == nil {
+					return nil
+				}
+				return []series.ICollector{s.topProcesses}
+			},
+		},
+		{
+			CollectorInfo: CollectorInfo{Name: CollectorPings, Dynamic: true},
+			Instances: func(s *Stats) []series.ICollector {
+				return toCollectors(s.pings)
+			},
+			Entries: func(instance series.ICollector) map[string]*series.Entry {
+				ping := instance.(*series.PingCollector)
+				return series.EntriesOf(ping, "ping", series.NamePart(ping.Name))
+			},
+		},
+		{
+			CollectorInfo: CollectorInfo{Name: CollectorDerived, Dynamic: true},
+			Instances: func(s *Stats) []series.ICollector {
+				return toCollectors(s.derived)
+			},
+		},
+	} {
+		RegisterCollector(reg)
+	}
+}
+
+// Returns copy of collectors slice as interfaces
+func toCollectors[T series.ICollector](collectors []T) []series.ICollector {
+	ret := make([]series.ICollector, len(collectors))
+	for x, c := range collectors {
+		ret[x] = c
+	}
+	return ret
+}
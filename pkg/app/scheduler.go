@@ -0,0 +1,254 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"n4/gui-test/pkg/series"
+)
+
+const (
+	// Number of collectors running at once
+	DefaultCollectorWorkers = 4
+	// Collection is given up after it, unless interval of collector is shorter
+	DefaultCollectorTimeout = 2 * time.Second
+
+	// Interval of failing collector is doubled up to this many times
+	maxBackoffShift = 4
+)
+
+// Collector of registry, instances of static ones never change
+type statsCollector struct {
+	info      CollectorInfo
+	instances func() []series.ICollector
+	entries   func(instance series.ICollector) map[string]*series.Entry
+
+	interval time.Duration
+	timeout  time.Duration
+	// NOTE: Collectors with the same interval are spread over it by offset
+	offset time.Duration
+	// NOTE: Guarded by statusLock, collection backs off when it fails
+	next time.Time
+
+	// NOTE: Set until collection returns, hung collector is not started again
+	running atomic.Bool
+
+	statusLock sync.Mutex
+	// Consecutive collections that failed
+	failures    int
+	healthState CollectorHealth
+	lastErr     error
+	errCount    int
+	// History of health and error count
+	health series.Entry
+	errors series.Entry
+
+	// Nanoseconds spent collecting since the last self update
+	spent atomic.Int64
+	// Seconds spent collecting per self update
+	update series.Entry
+}
+
+// Moves the next collection past now
+func (c *statsCollector) reschedule(now time.Time) {
+	c.statusLock.Lock()
+	defer c.statusLock.Unlock()
+	for !c.next.After(now) {
+		c.next = c.next.Add(c.interval)
+	}
+}
+
+// Delays the next collection of failing collector to its backed off
+// interval after start of the failed one. NOTE: Called on result, so the
+// collection after a success is not delayed
+func (c *statsCollector) backOff(start time.Time) {
+	c.statusLock.Lock()
+	defer c.statusLock.Unlock()
+	if c.failures == 0 {
+		return
+	}
+	next := start.Add(c.interval << min(c.failures, maxBackoffShift))
+	if next.After(c.next) {
+		c.next = next
+	}
+}
+
+func (c *statsCollector) getNext() time.Time {
+	c.statusLock.Lock()
+	defer c.statusLock.Unlock()
+	return c.next
+}
+
+// Spreads collectors with the same interval evenly over it to avoid bursts
+// of collection and sets intervals of self entries
+func (s *Stats) schedule() {
+	groups := make(map[time.Duration][]*statsCollector)
+	for _, c := range s.collectors {
+		groups[c.interval] = append(groups[c.interval], c)
+		c.timeout = c.info.Timeout
+		if c.timeout <= 0 {
+			c.timeout = min(c.interval, DefaultCollectorTimeout)
+		}
+	}
+	for interval, group := range groups {
+		for x, c := range group {
+			c.offset = interval * time.Duration(x) / time.Duration(len(group))
+		}
+	}
+
+	selfInterval := s.interval
+	for _, c := range s.collectors {
+		if c.info.Name == CollectorSelf {
+			selfInterval = c.interval
+		}
+	}
+	for _, entry := range []*series.Entry{
+		&s.StatsUpdate, &s.SelfFramerate, &s.RuntimeMemAlloc, &s.RuntimeMemSys,
+	} {
+		entry.SetInterval(selfInterval)
+	}
+	for _, c := range s.collectors {
+		c.update.SetInterval(selfInterval)
+		c.health.SetInterval(c.interval)
+		c.errors.SetInterval(c.interval)
+	}
+}
+
+// Starts collections of collectors due by now and returns when the next one
+// is due. Collections don't wait for each other, so a slow one doesn't delay
+// the rest. Missed collections are skipped, not caught up. Errors are
+// reported in collector status
+func (s *Stats) Update() time.Time {
+	now := time.Now()
+	if s.started.IsZero() {
+		s.started = now
+		for _, c := range s.collectors {
+			c.statusLock.Lock()
+			c.next = now.Add(c.offset)
+			c.statusLock.Unlock()
+		}
+	}
+
+	for _, c := range s.collectors {
+		if c.getNext().After(now) {
+			continue
+		}
+		if c.running.Load() {
+			// NOTE: Previous collection still hangs, its slot is a gap
+			for _, instance := range c.instances() {
+				series.AddGaps(instance)
+			}
+			c.report(errCollectorHangs)
+			c.reschedule(now)
+			c.backOff(now)
+			continue
+		}
+		c.reschedule(now)
+		c.running.Store(true)
+		s.inflight.Add(1)
+		go func() {
+			defer s.inflight.Done()
+			s.collect(c, now)
+		}()
+	}
+
+	var next time.Time
+	for _, c := range s.collectors {
+		if cNext := c.getNext(); next.IsZero() || cNext.Before(next) {
+			next = cNext
+		}
+	}
+	return next
+}
+
+var errCollectorHangs = errors.New("previous collection is still running")
+
+// Collects instances of collector in a worker until its timeout. Collection
+// that times out is left running and counted as failure
+func (s *Stats) collect(c *statsCollector, start time.Time) {
+	s.workers <- struct{}{}
+	defer func() { <-s.workers }()
+
+	perfStart := time.Now()
+	defer func() {
+		c.spent.Add(int64(time.Since(perfStart)))
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	result := make(chan error, 1)
+	go func() {
+		err := collectInstances(ctx, c.instances(), c.interval)
+		c.running.Store(false)
+		result <- err
+	}()
+
+	var err error
+	select {
+	case err = <-result:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		err = fmt.Errorf("collection timed out after %v: %w", c.timeout, err)
+	}
+	c.report(err)
+	c.backOff(start)
+	s.setUpdated(time.Now())
+}
+
+// Collects instances until ctx is done, the ones left are recorded as gaps.
+// Instances failing partially don't stop the rest
+func collectInstances(
+	ctx context.Context, instances []series.ICollector, interval time.Duration,
+) error {
+	var partialErrs []error
+	for _, instance := range instances {
+		if ctx.Err() != nil {
+			series.AddGaps(instance)
+			continue
+		}
+		series.SetEntriesInterval(instance, interval)
+
+		var err error
+		if ctxInstance, ok := instance.(series.IContextCollector); ok {
+			err = ctxInstance.CollectContext(ctx)
+		} else {
+			err = instance.Collect()
+		}
+		var partial *series.PartialError
+		if errors.As(err, &partial) {
+			partialErrs = append(partialErrs, err)
+		} else if err != nil {
+			return err
+		}
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if len(partialErrs) > 0 {
+		return &series.PartialError{Err: errors.Join(partialErrs...)}
+	}
+	return nil
+}
+
+// Updates stats on schedule until stop is closed, then waits for collections
+// in progress up to their timeout
+func (s *Stats) Run(stop <-chan struct{}) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-stop:
+			s.inflight.Wait()
+			return
+		case <-timer.C:
+			timer.Reset(time.Until(s.Update()))
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"n4/gui-test/pkg/series"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Collector counting its collections, collect blocks or fails if set
+type fakeCollector struct {
+	calls   atomic.Int32
+	collect func(ctx context.Context) error
+}
+
+func (c *fakeCollector) Collect() error {
+	return c.CollectContext(context.Background())
+}
+
+func (c *fakeCollector) CollectContext(ctx context.Context) error {
+	c.calls.Add(1)
+	if c.collect == nil {
+		return nil
+	}
+	return c.collect(ctx)
+}
+
+// Returns registration of dynamic collector with fixed instances
+func fakeRegistration(
+	name string, interval time.Duration, instances ...series.ICollector,
+) CollectorRegistration {
+	return CollectorRegistration{
+		CollectorInfo: CollectorInfo{Name: name, Interval: interval, Dynamic: true},
+		Instances:     func(*Stats) []series.ICollector { return instances },
+	}
+}
+
+func newTestStats(t *testing.T, regs ...CollectorRegistration) *Stats {
+	s, err := newStats(4, time.Second, regs)
+	require.NoError(t, err)
+	return s
+}
+
+// Returns collector of stats registered under name, fails if there is none
+func getTestCollector(t *testing.T, s *Stats, name string) *statsCollector {
+	c := s.getCollector(name)
+	require.NotNil(t, c, name)
+	return c
+}
+
+func TestStats_schedule(t *testing.T) {
+	s := newTestStats(t,
+		fakeRegistration("a", 300*time.Millisecond),
+		fakeRegistration("b", 300*time.Millisecond),
+		fakeRegistration("c", 300*time.Millisecond),
+		fakeRegistration("d", 0),
+		fakeRegistration("e", 5*time.Second),
+	)
+
+	tests := []struct {
+		name     string
+		interval time.Duration
+		offset   time.Duration
+		timeout  time.Duration
+	}{
+		{"a", 300 * time.Millisecond, 0, 300 * time.Millisecond},
+		{"b", 300 * time.Millisecond, 100 * time.Millisecond, 300 * time.Millisecond},
+		{"c", 300 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond},
+		// NOTE: Interval of stats is used unless collector has its own
+		{"d", time.Second, 0, time.Second},
+		{"e", 5 * time.Second, 0, DefaultCollectorTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := getTestCollector(t, s, tt.name)
+			assert.Equal(t, tt.interval, c.interval)
+			assert.Equal(t, tt.offset, c.offset)
+			assert.Equal(t, tt.timeout, c.timeout)
+		})
+	}
+}
+
+func TestStats_Update_DoesNotWait(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	slow := &fakeCollector{collect: func(context.Context) error {
+		// NOTE: Ignores context like a hung syscall
+		<-release
+		return nil
+	}}
+	fast := &fakeCollector{}
+	// NOTE: Intervals differ, so both are due at start
+	s := newTestStats(t,
+		fakeRegistration("slow", time.Hour, slow),
+		fakeRegistration("fast", time.Minute, fast),
+	)
+
+	start := time.Now()
+	s.Update()
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		return fast.calls.Load() == 1
+	}, time.Second, time.Millisecond)
+	status, _ := s.CollectorStatus("fast")
+	assert.Equal(t, HealthOK, status.Health)
+}
+
+func TestStats_Update_HangIsGap(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	hung := &fakeCollector{collect: func(context.Context) error {
+		<-release
+		return nil
+	}}
+	// NOTE: Gaps are added to entries of series collectors only
+	sysMem := series.NewSysMemCollector(4)
+	data := sysMem.Used.Subscribe(&series.Subscriber{})
+	s := newTestStats(t, fakeRegistration("hung", 20*time.Millisecond, hung, sysMem))
+	c := getTestCollector(t, s, "hung")
+
+	s.Update()
+	assert.Eventually(t, func() bool {
+		status, _ := s.CollectorStatus("hung")
+		return strings.Contains(status.LastError, "timed out")
+	}, time.Second, time.Millisecond)
+
+	// Collection is due again while the previous one still hangs
+	c.statusLock.Lock()
+	c.next = time.Now()
+	c.statusLock.Unlock()
+	s.Update()
+	assert.Equal(t, int32(1), hung.calls.Load())
+	status, _ := s.CollectorStatus("hung")
+	assert.Equal(t, errCollectorHangs.Error(), status.LastError)
+	assert.Equal(t, 2, status.Errors)
+	assert.True(t, math.IsNaN(data.GetFirstValue()))
+}
+
+func TestStats_collect_BackOff(t *testing.T) {
+	var fail atomic.Bool
+	fail.Store(true)
+	collector := &fakeCollector{collect: func(context.Context) error {
+		if fail.Load() {
+			return errors.New("failed")
+		}
+		return nil
+	}}
+	s := newTestStats(t, fakeRegistration("failing", 100*time.Millisecond, collector))
+	c := getTestCollector(t, s, "failing")
+
+	start := time.Now()
+	c.next = start
+	// Runs collection scheduled at start+at like Update does
+	collectAt := func(at time.Duration) {
+		c.reschedule(start.Add(at))
+		c.running.Store(true)
+		s.collect(c, start.Add(at))
+	}
+
+	collectAt(0)
+	assert.Equal(t, start.Add(200*time.Millisecond), c.getNext())
+	collectAt(200 * time.Millisecond)
+	assert.Equal(t, start.Add(600*time.Millisecond), c.getNext())
+
+	// NOTE: Backoff is capped
+	c.failures = 10
+	collectAt(600 * time.Millisecond)
+	assert.Equal(t, start.Add(600*time.Millisecond+1600*time.Millisecond), c.getNext())
+
+	fail.Store(false)
+	collectAt(2200 * time.Millisecond)
+	assert.Equal(t, start.Add(2300*time.Millisecond), c.getNext())
+}
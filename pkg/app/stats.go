@@ -1,9 +1,12 @@
 package app
 
 import (
+	"fmt"
 	"os"
 	"reflect"
 	"runtime"
+	"slices"
+	"sync"
 	"time"
 
 	"n4/gui-test/pkg/series"
@@ -19,110 +22,290 @@ func NewTickData(size int) *TickDataFloat64 {
 	return tickstore.NewTickData[float64](size)
 }
 
+// Time spent by collector to update its stats
+type CollectorUpdate struct {
+	Name  string
+	Entry *series.Entry
+}
+
+// Records runtime stats of the app itself
+type selfCollector struct {
+	s *Stats
+}
+
+func (c *selfCollector) Collect() error {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	c.s.RuntimeMemAlloc.AddValue(float64(memStats.Alloc))
+	c.s.RuntimeMemSys.AddValue(float64(memStats.Sys))
+	c.s.SelfFramerate.AddValue(ebiten.ActualTPS())
+
+	var total time.Duration
+	for _, collector := range c.s.collectors {
+		spent := time.Duration(collector.spent.Swap(0))
+		collector.update.AddValue(spent.Seconds())
+		total += spent
+	}
+	c.s.StatsUpdate.AddValue(total.Seconds())
+	return nil
+}
+
+// Stats of collectors from registry
 type Stats struct {
-	size    int
-	Updated time.Time
+	size     int
+	interval time.Duration
+	started  time.Time
+
+	updatedLock sync.Mutex
+	updated     time.Time
+	// NOTE: Collections in progress, stopped stats wait for them
+	inflight sync.WaitGroup
+
+	self *process.Process
 
 	StatsUpdate   series.Entry `json:"stats_update"`
 	SelfFramerate series.Entry `json:"self_framerate"`
 
-	SelfCPU *series.ProcessCPUCollector `json:"self_cpu"`
-	SelfMem *series.ProcessMemCollector `json:"self_mem"`
-
 	RuntimeMemAlloc series.Entry `json:"runtime_mem_alloc"`
 	RuntimeMemSys   series.Entry `json:"runtime_mem_sys"`
 
-	SysMem   *series.SysMemCollector   `json:"sys_mem"`
-	SysMemEx *series.SysMemExCollector `json:"sys_mem_ex"`
+	// NOTE: Instances of dynamic collectors, looked up through registry
+	procLock     sync.Mutex
+	processes    []*series.ProcessCollector
+	processTrees []*series.ProcessTreeCollector
+	topProcesses *series.TopProcessesCollector
+	pings        []*series.PingCollector
+	derived      []*series.DerivedCollector
+
+	collectors []*statsCollector
+	// NOTE: Semaphore of collector workers
+	workers chan struct{}
+}
 
-	Disks *series.DiskCollector `json:"disk"`
+// Returns stats keeping size samples of each entry. Collectors are updated
+// every interval unless registered with their own one
+func NewStats(size int, interval time.Duration) (*Stats, error) {
+	return newStats(size, interval, registered())
 }
 
-func NewStats(size int) *Stats {
+// Returns stats with collectors of registrations supported on this platform
+func newStats(size int, interval time.Duration, regs []CollectorRegistration) (*Stats, error) {
 	if size < 1 {
 		panic("size must be greater than zero")
 	}
+	if interval <= 0 {
+		panic("interval must be greater than zero")
+	}
 	proc, err := process.NewProcess(int32(os.Getpid()))
 	if err != nil {
-		panic(err) // FIXME: do not panic?
+		return nil, fmt.Errorf("failed to get own process: %w", err)
 	}
 
 	stats := Stats{
-		size: size,
+		size:     size,
+		interval: interval,
+
+		self: proc,
+
+		workers: make(chan struct{}, DefaultCollectorWorkers),
 
 		StatsUpdate:   *series.NewEntry(size),
 		SelfFramerate: *series.NewEntry(size),
 
-		SelfCPU: series.NewProcessCPUCollector(proc, size),
-		SelfMem: series.NewProcessMemCollector(proc, size),
-
 		RuntimeMemAlloc: *series.NewEntry(size),
 		RuntimeMemSys:   *series.NewEntry(size),
+	}
 
-		SysMem:   series.NewSysMemCollector(size),
-		SysMemEx: series.NewSysMemExCollector(size),
+	for _, reg := range regs {
+		if !reg.IsSupported() {
+			continue
+		}
+		var instances func() []series.ICollector
+		if reg.Dynamic {
+			instances = func() []series.ICollector { return reg.Instances(&stats) }
+		} else {
+			collector := reg.New(&stats)
+			if collector == nil {
+				continue
+			}
+			instances = func() []series.ICollector { return []series.ICollector{collector} }
+		}
+		c := &statsCollector{
+			info:      reg.CollectorInfo,
+			instances: instances,
+			entries:   reg.Entries,
 
-		Disks: series.NewDiskCollector(size),
+			interval: reg.Interval,
+
+			update: *series.NewEntry(size),
+			health: *series.NewEntry(size),
+			errors: *series.NewEntry(size),
+		}
+		if c.interval <= 0 {
+			c.interval = interval
+		}
+		stats.collectors = append(stats.collectors, c)
 	}
+	stats.schedule()
 
 	sub := &series.Subscriber{}
 	stats.StatsUpdate.Subscribe(sub)
 	stats.SelfFramerate.Subscribe(sub)
 	stats.RuntimeMemAlloc.Subscribe(sub)
 	stats.RuntimeMemSys.Subscribe(sub)
+	for _, c := range stats.collectors {
+		c.update.Subscribe(sub)
+	}
 
-	return &stats
+	return &stats, nil
 }
 
-func (s *Stats) getFieldPrefix(name string) string {
-	colType := reflect.TypeOf(s).Elem()
-	field, found := colType.FieldByName(name)
-	if !found {
-		panic("field not found")
-	}
-	prefix := field.Tag.Get("json")
-	// TODO: validate name(spaces, uniq, etc)
-	if prefix == "" {
-		panic("SeriesEntry must have 'json' tag")
+// Returns time the latest collection finished at
+func (s *Stats) GetUpdated() time.Time {
+	s.updatedLock.Lock()
+	defer s.updatedLock.Unlock()
+	return s.updated
+}
+
+func (s *Stats) setUpdated(at time.Time) {
+	s.updatedLock.Lock()
+	defer s.updatedLock.Unlock()
+	s.updated = at
+}
+
+// Returns collector registered under name, nil if it's not created by stats
+func (s *Stats) getCollector(name string) *statsCollector {
+	idx := slices.IndexFunc(s.collectors, func(c *statsCollector) bool {
+		return c.info.Name == name
+	})
+	if idx < 0 {
+		return nil
 	}
-	return prefix
+	return s.collectors[idx]
 }
 
-// FIXME: Handle errors
-func (s *Stats) Update() {
-	perfStart := time.Now()
-	defer func() {
-		s.StatsUpdate.GetData().AddValues(time.Since(perfStart).Seconds())
-	}()
+// Returns true if collector registered under name is created by stats
+func (s *Stats) HasCollector(name string) bool {
+	return s.getCollector(name) != nil
+}
 
-	var memStats runtime.MemStats
-	runtime.ReadMemStats(&memStats)
-	s.RuntimeMemAlloc.GetData().AddValues(float64(memStats.Alloc))
-	s.RuntimeMemSys.GetData().AddValues(float64(memStats.Sys))
-	s.SelfFramerate.GetData().AddValues(ebiten.ActualTPS())
+// Returns instances of collector registered under name, nil if it's not
+// created by stats
+func (s *Stats) Instances(name string) []series.ICollector {
+	c := s.getCollector(name)
+	if c == nil {
+		return nil
+	}
+	return c.instances()
+}
 
-	err := s.SelfCPU.Collect()
-	if err != nil {
-		panic(err) // FIXME: do not panic?
+// Returns instances of collector registered under name that are of type T
+func InstancesOf[T series.ICollector](s *Stats, name string) []T {
+	var ret []T
+	for _, instance := range s.Instances(name) {
+		if c, ok := instance.(T); ok {
+			ret = append(ret, c)
+		}
 	}
-	err = s.SelfMem.Collect()
-	if err != nil {
-		panic(err) // FIXME: do not panic?
+	return ret
+}
+
+// Returns the first instance of collector registered under name, zero value
+// if there is none, i.e. collector is not supported on this platform
+func CollectorOf[T series.ICollector](s *Stats, name string) T {
+	var zero T
+	instances := InstancesOf[T](s, name)
+	if len(instances) == 0 {
+		return zero
 	}
-	err = s.SysMem.Collect()
-	if err != nil {
-		panic(err) // FIXME: do not panic?
+	return instances[0]
+}
+
+// Overrides collection interval of collector. Must be called before updates
+func (s *Stats) SetCollectorInterval(name string, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("interval of collector %q must be greater than zero", name)
 	}
-	// FIXME: handle non-windows
-	err = s.SysMemEx.Collect()
-	if err != nil {
-		panic(err) // FIXME: do not panic?
+	c := s.getCollector(name)
+	if c == nil {
+		return fmt.Errorf("%w: %q", ErrCollectorUnavailable, name)
 	}
+	c.interval = interval
+	s.schedule()
+	return nil
+}
 
-	err = s.Disks.Collect()
-	if err != nil {
-		panic(err) // FIXME: do not panic?
+// Returns entries with time spent by each collector, in collection order
+func (s *Stats) CollectorUpdates() []CollectorUpdate {
+	updates := make([]CollectorUpdate, len(s.collectors))
+	for x, c := range s.collectors {
+		updates[x] = CollectorUpdate{Name: c.info.Name, Entry: &c.update}
 	}
+	return updates
+}
+
+// Adds collector of a process found by matcher
+func (s *Stats) AddProcess(name string, matcher series.ProcessMatcher) *series.ProcessCollector {
+	proc := series.NewProcessCollector(name, matcher, s.size)
+	s.AttachProcess(proc)
+	return proc
+}
+
+// Adds collector of a process tree with root found by matcher
+func (s *Stats) AddProcessTree(
+	name string, matcher series.ProcessMatcher, topN int,
+) *series.ProcessTreeCollector {
+	tree := series.NewProcessTreeCollector(name, matcher, topN, s.size)
+	s.procLock.Lock()
+	defer s.procLock.Unlock()
+	s.processTrees = append(s.processTrees, tree)
+	return tree
+}
+
+// Adds collector of processes using the most resources, n per metric
+func (s *Stats) AddTopProcesses(n int) *series.TopProcessesCollector {
+	top := series.NewTopProcessesCollector(n, s.size)
+	s.procLock.Lock()
+	defer s.procLock.Unlock()
+	s.topProcesses = top
+	return top
+}
+
+// Adds collector of latency to address
+func (s *Stats) AddPing(
+	name string, probe series.PingProbe, address string, timeout time.Duration,
+) *series.PingCollector {
+	ping := series.NewPingCollector(name, probe, address, timeout, s.size)
+	s.pings = append(s.pings, ping)
+	return ping
+}
+
+// Starts collecting stats of process collector. Safe to call during updates
+func (s *Stats) AttachProcess(proc *series.ProcessCollector) {
+	s.procLock.Lock()
+	defer s.procLock.Unlock()
+	s.processes = append(s.processes, proc)
+}
 
-	s.Updated = time.Now()
+// Stops collecting stats of process collector, its entries keep history
+func (s *Stats) DetachProcess(proc *series.ProcessCollector) {
+	s.procLock.Lock()
+	defer s.procLock.Unlock()
+	s.processes = slices.DeleteFunc(s.processes, func(p *series.ProcessCollector) bool {
+		return p == proc
+	})
+}
+
+func (s *Stats) getFieldPrefix(name string) string {
+	colType := reflect.TypeOf(s).Elem()
+	field, found := colType.FieldByName(name)
+	if !found {
+		panic("field not found")
+	}
+	prefix := field.Tag.Get("json")
+	// TODO: validate name(spaces, uniq, etc)
+	if prefix == "" {
+		panic("SeriesEntry must have 'json' tag")
+	}
+	return prefix
 }
@@ -0,0 +1,35 @@
+package app
+
+import (
+	"time"
+
+	"n4/gui-test/pkg/series"
+)
+
+func init() {
+	RegisterCollector(CollectorRegistration{
+		CollectorInfo: CollectorInfo{
+			Name:      CollectorSensors,
+			Platforms: []string{"linux"},
+		},
+		New: func(s *Stats) series.ICollector {
+			sensors := series.NewSensorsCollector(series.SysfsRoot, s.size)
+			// NOTE: Sensors are prefetched to be used in graph init, sysfs
+			// glob errors only on malformed pattern
+			_ = sensors.Discover()
+			return sensors
+		},
+	})
+	RegisterCollector(CollectorRegistration{
+		CollectorInfo: CollectorInfo{
+			Name:      CollectorPower,
+			Platforms: []string{"linux"},
+			Interval:  5 * time.Second,
+		},
+		New: func(s *Stats) series.ICollector {
+			power := series.NewPowerSupplyCollector(series.SysfsRoot, s.size)
+			_ = power.Discover()
+			return power
+		},
+	})
+}
@@ -0,0 +1,17 @@
+//go:build windows || linux
+
+package app
+
+import "n4/gui-test/pkg/series"
+
+func init() {
+	RegisterCollector(CollectorRegistration{
+		CollectorInfo: CollectorInfo{
+			Name:      CollectorSysMemEx,
+			Platforms: []string{"windows", "linux"},
+		},
+		New: func(s *Stats) series.ICollector {
+			return series.NewSysMemExCollector(s.size)
+		},
+	})
+}
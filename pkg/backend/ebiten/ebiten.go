@@ -7,6 +7,8 @@ import (
 	"n4/gui-test/pkg/app"
 	"n4/gui-test/pkg/config"
 	"n4/gui-test/pkg/graph"
+	"n4/gui-test/pkg/plot"
+	"n4/gui-test/pkg/series"
 
 	"github.com/ebitengine/microui"
 	"github.com/hajimehoshi/ebiten/v2"
@@ -42,7 +44,13 @@ type Game struct {
 	stats        *app.Stats
 	statsUpdated time.Time
 
-	graphs graph.Collection
+	graphs        graph.Collection
+	graphsUpdates <-chan graph.Update
+
+	topSub      series.Subscriber
+	pinRequests chan<- int32
+
+	plotStyle plot.Style
 
 	input      bool
 	introShown bool
@@ -110,6 +118,12 @@ func (g *Game) getPlotColumnWidth() int {
 		timeRange*g.cfg.App.BarSpacing
 }
 
+// TODO: Don't depend on (g *Game)?
+func (g *Game) getPlotWidth() int {
+	timeRange := g.cfg.App.TimeRangeSeconds
+	return timeRange*g.cfg.App.BarWidth + (timeRange-1)*g.cfg.App.BarSpacing
+}
+
 // TODO: Don't depend on (g *Game)?
 func (g *Game) getPlotColumnHeight() int {
 	plotsCount := g.graphs.ActiveNum()
@@ -147,13 +161,21 @@ func (g *Game) isSettingsShown() bool {
 	return g.showSettings && !ebiten.IsWindowMousePassthrough()
 }
 
+// Returns size of plots and panels beside them
+func (g *Game) getMainWindowSize() (int, int) {
+	width, height := g.getPlotColumnWidth(), g.getPlotColumnHeight()
+	if g.isTopProcessesShown() {
+		width += g.ctx.Style.Spacing + topPanelWidth
+		height = max(height, g.getTopPanelHeight())
+	}
+	return width, height
+}
+
 func (g *Game) updateSize() {
+	g.width, g.height = g.getMainWindowSize()
 	if g.isSettingsShown() {
-		g.width = max(g.getPlotColumnWidth(), g.getSettingsWindowWidth())
-		g.height = max(g.getPlotColumnHeight(), g.getSettingsWindowHeight())
-	} else {
-		g.width = g.getPlotColumnWidth()
-		g.height = g.getPlotColumnHeight()
+		g.width = max(g.width, g.getSettingsWindowWidth())
+		g.height = max(g.height, g.getSettingsWindowHeight())
 	}
 	ebiten.SetWindowSize(g.width, g.height)
 }
@@ -168,6 +190,13 @@ func (g *Game) Update() error {
 		// NOTE: MouseButtonLeft helps to avoid flickering when closing settings
 		ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft)
 
+	select {
+	case update := <-g.graphsUpdates:
+		g.graphs = update.Apply()
+		g.updateSize()
+	default:
+	}
+
 	g.handleDrag()
 
 	g.ProcessFrame()
@@ -179,7 +208,7 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	draw := g.input ||
 		g.isSettingsShown() ||
 		!g.introShown ||
-		g.stats.Updated.After(g.statsUpdated)
+		g.stats.GetUpdated().After(g.statsUpdated)
 	if !draw {
 		return
 	}
@@ -195,7 +224,7 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	g.ctx.Draw(screen)
 
 	g.introShown = true
-	g.statsUpdated = g.stats.Updated
+	g.statsUpdated = g.stats.GetUpdated()
 }
 
 func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
@@ -217,6 +246,8 @@ func Window(
 	stats *app.Stats,
 	togglePassthrough <-chan struct{},
 	exit <-chan struct{},
+	graphsUpdates <-chan graph.Update,
+	pinRequests chan<- int32,
 ) {
 	logger, err := zap.NewProduction()
 	if err != nil {
@@ -232,23 +263,29 @@ func Window(
 	ebiten.SetWindowPosition(cfg.App.Position.X, cfg.App.Position.Y)
 	ebiten.SetVsyncEnabled(false)
 
+	plotStyle, err := plot.ParseStyle(cfg.App.PlotStyle)
+	if err != nil {
+		logger.Warn("fallback to default plot style", zap.Error(err))
+	}
+
 	game := &Game{
 		ctx: microui.NewContext(),
 		cfg: cfg,
 
-		stats:  stats,
-		graphs: graphs,
+		stats:         stats,
+		graphs:        graphs,
+		graphsUpdates: graphsUpdates,
+
+		pinRequests: pinRequests,
+
+		plotStyle: plotStyle,
 	}
 	game.ctx.Style.Padding = 2
 	game.ctx.Style.Spacing = 2
 
+	game.setTopProcessesShown(cfg.App.TopProcesses.Enabled)
 	game.setPassthrough(false)
 
-	game.width = game.getPlotColumnWidth()
-	game.height = game.getPlotColumnHeight()
-
-	ebiten.SetWindowSize(game.width, game.height)
-
 	go func() {
 		<-exit
 		logger.Info("We're exitting...")
@@ -6,7 +6,6 @@ import (
 	"image/color"
 	"log"
 
-	"n4/gui-test/pkg/bitflags"
 	"n4/gui-test/pkg/plot"
 	"n4/gui-test/resources/fonts"
 
@@ -16,7 +15,12 @@ import (
 	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
-var fontFace *text.GoTextFace
+var (
+	fontFace *text.GoTextFace
+
+	whiteImage    = ebiten.NewImage(3, 3)
+	whiteSubImage = whiteImage.SubImage(image.Rect(1, 1, 2, 2)).(*ebiten.Image)
+)
 
 func init() {
 	// TODO: Implement setting of font face and size in config
@@ -33,6 +37,8 @@ func init() {
 		Source: source,
 		Size:   10,
 	}
+
+	whiteImage.Fill(color.White)
 }
 
 func textWidth(str string) int {
@@ -47,6 +53,12 @@ func (g *Game) DrawPlot(widget *plot.Widget) {
 	g.ctx.Control(0, 0, func(r image.Rectangle) microui.Res {
 		widget.SetSize(r.Dx(), r.Dy())
 
+		theme := g.cfg.App.Theme.Plot
+
+		borderColor := theme.Border
+		if level, exceeded := widget.GetExceededLevel(); exceeded {
+			borderColor = theme.ThresholdColor(level == plot.ThresholdCritical)
+		}
 		for _, border := range widget.GetBorders() {
 			if border == nil {
 				continue
@@ -62,7 +74,7 @@ func (g *Game) DrawPlot(widget *plot.Widget) {
 					float32(rectGlobal.Min.Y),
 					float32(rectGlobal.Dx()),
 					float32(rectGlobal.Dy()),
-					g.cfg.App.Theme.Plot.Border,
+					borderColor,
 					false)
 			})
 		}
@@ -88,71 +100,130 @@ func (g *Game) DrawPlot(widget *plot.Widget) {
 			}
 		}
 
-		for x := range widget.GetData() {
-			barRect := widget.GetValueRect(x).Canon()
-			if !barRect.Empty() {
-				rectGlobal := barRect.Add(r.Min)
+		for _, line := range widget.GetGridLines() {
+			rectGlobal := line.Add(r.Min)
+			g.ctx.DrawControl(func(screen *ebiten.Image) {
+				vector.DrawFilledRect(
+					screen,
+					float32(rectGlobal.Min.X),
+					float32(rectGlobal.Min.Y),
+					float32(rectGlobal.Dx()),
+					float32(rectGlobal.Dy()),
+					theme.Grid,
+					false)
+			})
+		}
+
+		for _, tick := range widget.GetTimeTicks() {
+			rectGlobal := tick.Add(r.Min)
+			g.ctx.DrawControl(func(screen *ebiten.Image) {
+				vector.DrawFilledRect(
+					screen,
+					float32(rectGlobal.Min.X),
+					float32(rectGlobal.Min.Y),
+					float32(rectGlobal.Dx()),
+					float32(rectGlobal.Dy()),
+					theme.Tick,
+					false)
+			})
+		}
+
+		for _, line := range widget.GetThresholdLines() {
+			rectGlobal := line.Rect.Add(r.Min)
+			lineColor := theme.ThresholdColor(line.Level == plot.ThresholdCritical)
+			g.ctx.DrawControl(func(screen *ebiten.Image) {
+				vector.DrawFilledRect(
+					screen,
+					float32(rectGlobal.Min.X),
+					float32(rectGlobal.Min.Y),
+					float32(rectGlobal.Dx()),
+					float32(rectGlobal.Dy()),
+					lineColor,
+					false)
+			})
+		}
+
+		for _, polygon := range widget.GetEnvelope() {
+			g.ctx.DrawControl(func(screen *ebiten.Image) {
+				fillPolygon(screen, r.Min, polygon, theme.Envelope)
+			})
+		}
+
+		multiSeries := len(widget.GetSeries()) > 1
+
+		for idx, wSeries := range widget.GetSeries() {
+			barColor, lineColor, fillColor := theme.Bar, theme.Line, theme.Fill
+			if multiSeries {
+				barColor, lineColor, fillColor = theme.SeriesColors(idx)
+			}
+
+			for _, polygon := range widget.GetPolygons(idx) {
 				g.ctx.DrawControl(func(screen *ebiten.Image) {
-					vector.DrawFilledRect(
-						screen,
-						float32(rectGlobal.Min.X),
-						float32(rectGlobal.Min.Y),
-						float32(rectGlobal.Dx()),
-						float32(rectGlobal.Dy()),
-						g.cfg.App.Theme.Plot.Bar,
-						false)
+					if widget.Style == plot.StyleGradientArea {
+						fillPolygonGradient(
+							screen, r.Min, polygon, fillColor,
+							widget.GetGradientRatio,
+						)
+					} else {
+						fillPolygon(screen, r.Min, polygon, fillColor)
+					}
 				})
 			}
-		}
 
-		if bitflags.Has(widget.Flags, plot.FlagsLabelsAll) {
-			xMin, xMax := widget.GetSanitizedMinMax()
-
-			labels := []struct {
-				text string
-				pos  image.Point
-			}{
-				{
-					text: widget.Label,
-					pos:  r.Min.Add(widget.LabelPadding),
-				},
-				{
-					text: widget.FormatCallback(widget.GetData()[0]),
-					pos: r.Min.Add(image.Pt(
-						widget.LabelPadding.X,
-						widget.LabelPadding.Y+lineHeight(),
-					)),
-				},
-				{
-					text: widget.FormatCallback(xMax),
-					pos: r.Min.Add(image.Pt(
-						widget.Width-textWidth(widget.FormatCallback(xMax))-
-							widget.LabelPadding.X,
-						widget.LabelPadding.Y,
-					)),
-				},
-				{
-					text: widget.FormatCallback(xMin),
-					pos: r.Min.Add(image.Pt(
-						widget.Width-textWidth(widget.FormatCallback(xMin))-widget.LabelPadding.X,
-						widget.Height-lineHeight()-widget.LabelPadding.Y,
-					)),
-				},
+			for _, line := range widget.GetPolylines(idx) {
+				g.ctx.DrawControl(func(screen *ebiten.Image) {
+					strokePolyline(screen, r.Min, line, lineColor)
+				})
 			}
 
-			for _, label := range labels {
-				labelRect := image.Rectangle{
-					label.pos, image.Point{textWidth(label.text), lineHeight()},
+			if widget.Style != plot.StyleBar {
+				continue
+			}
+			for x := range wSeries.Data {
+				barRect := widget.GetValueRect(idx, x).Canon()
+				if !barRect.Empty() {
+					rectGlobal := barRect.Add(r.Min)
+					g.ctx.DrawControl(func(screen *ebiten.Image) {
+						vector.DrawFilledRect(
+							screen,
+							float32(rectGlobal.Min.X),
+							float32(rectGlobal.Min.Y),
+							float32(rectGlobal.Dx()),
+							float32(rectGlobal.Dy()),
+							barColor,
+							false)
+					})
 				}
-				g.ctx.DrawControl(func(screen *ebiten.Image) {
-					op := &text.DrawOptions{}
-					op.GeoM.Translate(float64(label.pos.X), float64(label.pos.Y))
-					op.ColorScale.ScaleWithColor(g.cfg.App.Theme.Plot.LabelText)
-					text.Draw(screen, label.text, fontFace, op)
+			}
+		}
 
-					drawFilledRect(screen, labelRect, g.cfg.App.Theme.Plot.LabelBackground)
-				})
+		for _, overlay := range widget.GetOverlayLines() {
+			lineColor := theme.OverlayColor(overlay.Kind == plot.OverlayP95)
+			g.ctx.DrawControl(func(screen *ebiten.Image) {
+				strokePolyline(screen, r.Min, overlay.Line, lineColor)
+			})
+		}
+
+		for _, label := range widget.GetLabels(textWidth, lineHeight()) {
+			pos := r.Min.Add(label.Pos)
+			labelRect := image.Rectangle{
+				pos, image.Point{textWidth(label.Text), lineHeight()},
+			}
+			labelColor := theme.LabelText
+			if multiSeries && label.SeriesIdx >= 0 {
+				labelColor, _, _ = theme.SeriesColors(label.SeriesIdx)
 			}
+			if label.Error {
+				labelColor = theme.Critical
+			}
+			g.ctx.DrawControl(func(screen *ebiten.Image) {
+				op := &text.DrawOptions{}
+				op.GeoM.Translate(float64(pos.X), float64(pos.Y))
+				op.ColorScale.ScaleWithColor(labelColor)
+				text.Draw(screen, label.Text, fontFace, op)
+
+				drawFilledRect(screen, labelRect, theme.LabelBackground)
+			})
 		}
 
 		return 0
@@ -169,3 +240,90 @@ func drawFilledRect(screen *ebiten.Image, rect image.Rectangle, color color.RGBA
 		color,
 		false)
 }
+
+func pointsToPath(offset image.Point, points []image.Point) *vector.Path {
+	var path vector.Path
+	for idx, pt := range points {
+		pt = pt.Add(offset)
+		// NOTE: Pixel centers to keep 1px lines crisp
+		x, y := float32(pt.X)+0.5, float32(pt.Y)+0.5
+		if idx == 0 {
+			path.MoveTo(x, y)
+		} else {
+			path.LineTo(x, y)
+		}
+	}
+	return &path
+}
+
+func drawVertices(
+	screen *ebiten.Image,
+	vs []ebiten.Vertex,
+	is []uint16,
+	color color.RGBA,
+	fillRule ebiten.FillRule,
+	colorScale func(v *ebiten.Vertex) float32,
+) {
+	r, g, b, a := color.RGBA()
+	for i := range vs {
+		scale := float32(1)
+		if colorScale != nil {
+			scale = colorScale(&vs[i])
+		}
+		vs[i].SrcX = 1
+		vs[i].SrcY = 1
+		vs[i].ColorR = float32(r) / 0xffff * scale
+		vs[i].ColorG = float32(g) / 0xffff * scale
+		vs[i].ColorB = float32(b) / 0xffff * scale
+		vs[i].ColorA = float32(a) / 0xffff * scale
+	}
+
+	op := &ebiten.DrawTrianglesOptions{}
+	op.ColorScaleMode = ebiten.ColorScaleModePremultipliedAlpha
+	op.FillRule = fillRule
+	screen.DrawTriangles(vs, is, whiteSubImage, op)
+}
+
+func strokePolyline(
+	screen *ebiten.Image, offset image.Point, line plot.Polyline, color color.RGBA,
+) {
+	if len(line) == 1 {
+		pt := line[0].Add(offset)
+		vector.DrawFilledRect(
+			screen, float32(pt.X), float32(pt.Y), 1, 1, color, false,
+		)
+		return
+	}
+
+	path := pointsToPath(offset, line)
+	vs, is := path.AppendVerticesAndIndicesForStroke(
+		nil, nil, &vector.StrokeOptions{Width: 1, LineJoin: vector.LineJoinRound},
+	)
+	drawVertices(screen, vs, is, color, ebiten.FillRuleNonZero, nil)
+}
+
+func fillPolygon(
+	screen *ebiten.Image, offset image.Point, polygon plot.Polygon, color color.RGBA,
+) {
+	path := pointsToPath(offset, polygon)
+	path.Close()
+	vs, is := path.AppendVerticesAndIndicesForFilling(nil, nil)
+	drawVertices(screen, vs, is, color, ebiten.FillRuleNonZero, nil)
+}
+
+func fillPolygonGradient(
+	screen *ebiten.Image,
+	offset image.Point,
+	polygon plot.Polygon,
+	color color.RGBA,
+	ratio func(y float64) float64,
+) {
+	path := pointsToPath(offset, polygon)
+	path.Close()
+	vs, is := path.AppendVerticesAndIndicesForFilling(nil, nil)
+	drawVertices(screen, vs, is, color, ebiten.FillRuleNonZero,
+		func(v *ebiten.Vertex) float32 {
+			return float32(ratio(float64(v.DstY) - 0.5 - float64(offset.Y)))
+		},
+	)
+}
@@ -0,0 +1,156 @@
+package ebiten
+
+import (
+	"image"
+	"math"
+	"unicode/utf8"
+
+	"n4/gui-test/pkg/app"
+	"n4/gui-test/pkg/graph"
+	"n4/gui-test/pkg/series"
+
+	"github.com/ebitengine/microui"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	topPanelWidth     = 200
+	topNameWidth      = 80
+	topValueWidth     = 60
+	topSparkBarWidth  = 2
+	topSparkMinHeight = 1
+)
+
+// Returns top processes collector, nil if it's not added to stats
+func (g *Game) getTopProcesses() *series.TopProcessesCollector {
+	return app.CollectorOf[*series.TopProcessesCollector](g.stats, app.CollectorTopProcesses)
+}
+
+func (g *Game) isTopProcessesShown() bool {
+	return g.cfg.App.TopProcesses.Enabled && g.getTopProcesses() != nil
+}
+
+// Subscribes to top processes, so they are collected only while shown
+func (g *Game) setTopProcessesShown(show bool) {
+	top := g.getTopProcesses()
+	if top == nil {
+		return
+	}
+	if show && !top.Count.IsActive() {
+		top.Count.Subscribe(&g.topSub)
+	}
+	if !show && top.Count.IsActive() {
+		top.Count.Unsubscribe(&g.topSub)
+	}
+	g.cfg.App.TopProcesses.Enabled = show
+}
+
+func (g *Game) toggleTopProcesses() {
+	g.setTopProcessesShown(!g.isTopProcessesShown())
+	g.cfg.Save()
+	g.updateSize()
+}
+
+// TODO: Don't depend on (g *Game)?
+func (g *Game) getTopPanelHeight() int {
+	rowsPerMetric := 1 + g.cfg.App.TopProcesses.Count
+	rows := rowsPerMetric * len(series.TopMetrics())
+	return g.ctx.Style.Spacing + (g.ctx.Style.Spacing+lineHeight())*rows
+}
+
+func (g *Game) drawTopProcesses() {
+	top := g.getTopProcesses()
+	g.ctx.LayoutColumn(func() {
+		for _, metric := range series.TopMetrics() {
+			g.ctx.SetLayoutRow([]int{-1}, lineHeight())
+			g.ctx.Label("Top " + metric.String())
+			for _, proc := range top.GetTop(metric) {
+				g.drawTopProcess(metric, proc)
+			}
+		}
+	})
+}
+
+// Draws row with name, value and sparkline of the process. Clicking the row
+// in interactive mode pins the process
+func (g *Game) drawTopProcess(metric series.TopMetric, proc series.TopProcess) {
+	g.ctx.Control(0, 0, func(r image.Rectangle) microui.Res {
+		theme := g.cfg.App.Theme.Plot
+
+		name := proc.Name
+		for name != "" && textWidth(name) > topNameWidth-g.ctx.Style.Padding {
+			_, size := utf8.DecodeLastRuneInString(name)
+			name = name[:len(name)-size]
+		}
+		value := graph.FormatTopValue(metric, proc.Value)
+		valuePos := image.Pt(
+			r.Min.X+topNameWidth+topValueWidth-textWidth(value)-g.ctx.Style.Padding,
+			r.Min.Y,
+		)
+		g.ctx.DrawControl(func(screen *ebiten.Image) {
+			for _, label := range []struct {
+				text string
+				pos  image.Point
+			}{
+				{name, r.Min},
+				{value, valuePos},
+			} {
+				op := &text.DrawOptions{}
+				op.GeoM.Translate(float64(label.pos.X), float64(label.pos.Y))
+				op.ColorScale.ScaleWithColor(theme.LabelText)
+				text.Draw(screen, label.text, fontFace, op)
+			}
+		})
+
+		spark := image.Rect(r.Min.X+topNameWidth+topValueWidth, r.Min.Y, r.Max.X, r.Max.Y)
+		samples := min(spark.Dx()/topSparkBarWidth, len(proc.History))
+		peak := 0.0
+		for _, val := range proc.History[:samples] {
+			if !math.IsNaN(val) {
+				peak = max(peak, val)
+			}
+		}
+		// NOTE: The latest value is the first one, draw it on the right
+		for x, val := range proc.History[:samples] {
+			if math.IsNaN(val) || peak <= 0 {
+				continue
+			}
+			height := max(int(val/peak*float64(spark.Dy())), topSparkMinHeight)
+			bar := image.Rect(
+				spark.Max.X-(x+1)*topSparkBarWidth, spark.Max.Y-height,
+				spark.Max.X-x*topSparkBarWidth, spark.Max.Y,
+			)
+			g.ctx.DrawControl(func(screen *ebiten.Image) {
+				vector.DrawFilledRect(
+					screen,
+					float32(bar.Min.X),
+					float32(bar.Min.Y),
+					float32(bar.Dx()),
+					float32(bar.Dy()),
+					theme.Line,
+					false)
+			})
+		}
+
+		// NOTE: Main window doesn't interact, so clicks are checked directly
+		if !ebiten.IsWindowMousePassthrough() && !g.isSettingsShown() &&
+			inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) &&
+			image.Pt(ebiten.CursorPosition()).In(r) {
+			g.pinProcess(proc.PID)
+		}
+
+		return 0
+	})
+}
+
+// Requests graphs of the process without blocking the frame, request is
+// dropped if previous ones are not handled yet
+func (g *Game) pinProcess(pid int32) {
+	select {
+	case g.pinRequests <- pid:
+	default:
+	}
+}
@@ -3,6 +3,7 @@ package ebiten
 import (
 	"image"
 	"slices"
+	"time"
 
 	"n4/gui-test/pkg/plot"
 
@@ -105,6 +106,24 @@ func (g *Game) drawSettings() {
 			g.updateSize()
 		}
 
+		g.ctx.Label("Plot Style")
+		if g.ctx.Button(g.plotStyle.String()) != 0 {
+			g.plotStyle = g.plotStyle.Next()
+			g.cfg.App.PlotStyle = g.plotStyle.String()
+			g.cfg.Save()
+		}
+
+		if g.getTopProcesses() != nil {
+			g.ctx.Label("Top Processes")
+			topText := "off"
+			if g.isTopProcessesShown() {
+				topText = "on"
+			}
+			if g.ctx.Button(topText) != 0 {
+				g.toggleTopProcesses()
+			}
+		}
+
 		g.ctx.SetLayoutRow(slices.Repeat(
 			[]int{settingsBtnWidth, settingsDescriptionWidth},
 			settingsBtnNumInRow,
@@ -166,7 +185,11 @@ func (g *Game) mainWindow() {
 		microui.OptNoInteract
 	rect := image.Rect(0, 0, g.width, g.height)
 	g.ctx.WindowEx("Main", rect, flags, func(_ microui.Res) {
-		g.ctx.SetLayoutRow([]int{0, -1}, 0)
+		if g.isTopProcessesShown() {
+			g.ctx.SetLayoutRow([]int{g.getPlotWidth(), -1}, 0)
+		} else {
+			g.ctx.SetLayoutRow([]int{0, -1}, 0)
+		}
 
 		g.ctx.LayoutColumn(func() {
 			if !ebiten.IsWindowMousePassthrough() {
@@ -178,30 +201,30 @@ func (g *Game) mainWindow() {
 				}
 			}
 
-			tRange := g.cfg.App.TimeRangeSeconds
-			g.ctx.SetLayoutRow([]int{
-				tRange*g.cfg.App.BarWidth + (tRange-1)*g.cfg.App.BarSpacing,
-			}, g.cfg.App.PlotHeight)
+			g.ctx.SetLayoutRow([]int{g.getPlotWidth()}, g.cfg.App.PlotHeight)
 
-			for _, graph := range g.graphs {
-				if !graph.IsActive() {
-					continue
-				}
-				graph.Update()
-				data := graph.GetData().GetValues()
-				plotWidget := plot.NewWidget(graph.NameLabel, data).
+			// NOTE: Error text is shown only in interactive mode, badge is
+			// enough to notice it
+			flags := plot.FlagsDebugIgnoreCanvasBounds | plot.FlagsAutoKeepMinMax
+			if !ebiten.IsWindowMousePassthrough() {
+				flags |= plot.FlagsErrorText
+			}
+			for _, plotWidget := range g.graphs.NewPlotWidgets() {
+				plotWidget.
 					// SetSize(r.Dx(), r.Dy()).
-					SetLimits(graph.Limits.Min, graph.Limits.Max).
-					SetAutoHeightPadding(graph.AutoMinMaxPadding).
 					SetBarSize(g.cfg.App.BarWidth, g.cfg.App.BarSpacing).
-					SetFlags(
-						plot.FlagsDebugIgnoreCanvasBounds|
-							plot.FlagsAutoKeepMinMax,
-						false).
-					SetFormatCallback(graph.ValueLabelFormatCb)
+					SetFlags(flags, false).
+					SetDefaultSampleInterval(
+						time.Duration(g.cfg.App.UpdateRateSeconds) * time.Second,
+					).
+					SetStyle(g.plotStyle)
 				g.DrawPlot(plotWidget)
 			}
 		})
+
+		if g.isTopProcessesShown() {
+			g.drawTopProcesses()
+		}
 	})
 }
 
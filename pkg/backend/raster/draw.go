@@ -0,0 +1,120 @@
+package raster
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"n4/gui-test/pkg/plot"
+
+	"golang.org/x/image/vector"
+)
+
+// NOTE: Theme colors are not valid alpha-premultiplied colors(e.g. R > A).
+// GPU clamps blending result, but image/draw overflows, so colors are treated
+// as non-premultiplied
+func newUniform(clr color.RGBA) *image.Uniform {
+	return image.NewUniform(color.NRGBA(clr))
+}
+
+func fillRect(dst *image.RGBA, rect image.Rectangle, clr color.RGBA) {
+	if rect.Empty() {
+		return
+	}
+	draw.Draw(dst, rect, newUniform(clr), image.Point{}, draw.Over)
+}
+
+func newRasterizer(dst *image.RGBA) *vector.Rasterizer {
+	z := vector.NewRasterizer(dst.Bounds().Dx(), dst.Bounds().Dy())
+	z.DrawOp = draw.Over
+	return z
+}
+
+func addPolygon(z *vector.Rasterizer, offset image.Point, points []image.Point) {
+	for idx, pt := range points {
+		pt = pt.Add(offset)
+		// NOTE: Pixel centers to match ebiten backend
+		x, y := float32(pt.X)+0.5, float32(pt.Y)+0.5
+		if idx == 0 {
+			z.MoveTo(x, y)
+		} else {
+			z.LineTo(x, y)
+		}
+	}
+	z.ClosePath()
+}
+
+func fillPolygon(dst *image.RGBA, offset image.Point, polygon plot.Polygon, clr color.RGBA) {
+	z := newRasterizer(dst)
+	addPolygon(z, offset, polygon)
+	z.Draw(dst, dst.Bounds(), newUniform(clr), image.Point{})
+}
+
+func fillPolygonGradient(
+	dst *image.RGBA,
+	offset image.Point,
+	polygon plot.Polygon,
+	clr color.RGBA,
+	ratio func(y float64) float64,
+) {
+	z := newRasterizer(dst)
+	addPolygon(z, offset, polygon)
+	src := &gradientImage{color: clr, ratio: ratio, offsetY: offset.Y}
+	z.Draw(dst, dst.Bounds(), src, image.Point{})
+}
+
+// Strokes 1px wide line as a set of quads along the segments
+func strokePolyline(dst *image.RGBA, offset image.Point, line plot.Polyline, clr color.RGBA) {
+	if len(line) == 1 {
+		pt := line[0].Add(offset)
+		fillRect(dst, image.Rectangle{pt, pt.Add(image.Pt(1, 1))}, clr)
+		return
+	}
+
+	z := newRasterizer(dst)
+	for idx := 1; idx < len(line); idx++ {
+		p0, p1 := line[idx-1].Add(offset), line[idx].Add(offset)
+		x0, y0 := float64(p0.X)+0.5, float64(p0.Y)+0.5
+		x1, y1 := float64(p1.X)+0.5, float64(p1.Y)+0.5
+		dx, dy := x1-x0, y1-y0
+		length := math.Hypot(dx, dy)
+		if length == 0 {
+			continue
+		}
+		// NOTE: Half-width normal and tangent. Tangent extends segment ends to
+		// close gaps on joints
+		nx, ny := float32(-dy/length*0.5), float32(dx/length*0.5)
+		tx, ty := float32(dx/length*0.5), float32(dy/length*0.5)
+		ax, ay := float32(x0)-tx, float32(y0)-ty
+		bx, by := float32(x1)+tx, float32(y1)+ty
+		z.MoveTo(ax+nx, ay+ny)
+		z.LineTo(bx+nx, by+ny)
+		z.LineTo(bx-nx, by-ny)
+		z.LineTo(ax-nx, ay-ny)
+		z.ClosePath()
+	}
+	z.Draw(dst, dst.Bounds(), newUniform(clr), image.Point{})
+}
+
+// Infinite image with color faded according to the row
+type gradientImage struct {
+	color   color.RGBA
+	ratio   func(y float64) float64
+	offsetY int
+}
+
+func (g *gradientImage) ColorModel() color.Model {
+	return color.NRGBAModel
+}
+
+func (g *gradientImage) Bounds() image.Rectangle {
+	return image.Rect(-1e9, -1e9, 1e9, 1e9)
+}
+
+func (g *gradientImage) At(_, y int) color.Color {
+	scale := g.ratio(float64(y - g.offsetY))
+	clr := color.NRGBA(g.color)
+	clr.A = uint8(float64(clr.A) * scale)
+	return clr
+}
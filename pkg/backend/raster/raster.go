@@ -0,0 +1,187 @@
+package raster
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"io"
+	"time"
+
+	"n4/gui-test/pkg/config"
+	"n4/gui-test/pkg/plot"
+
+	"go.uber.org/zap"
+	"golang.org/x/image/font"
+)
+
+const (
+	// NOTE: Matches microui style of the ebiten backend
+	padding = 2
+	spacing = 2
+)
+
+// Renders plot widgets into an image without GPU or window
+type Renderer struct {
+	cfg  *config.Config
+	face font.Face
+
+	plotStyle plot.Style
+}
+
+// Returns renderer of cfg. Unknown plot style falls back to the default one
+// with a warning, the same way as the overlay does
+func NewRenderer(logger *zap.Logger, cfg *config.Config) (*Renderer, error) {
+	face, err := newFontFace()
+	if err != nil {
+		return nil, err
+	}
+	plotStyle, err := plot.ParseStyle(cfg.App.PlotStyle)
+	if err != nil {
+		logger.Warn("fallback to default plot style", zap.Error(err))
+	}
+	return &Renderer{
+		cfg:  cfg,
+		face: face,
+
+		plotStyle: plotStyle,
+	}, nil
+}
+
+func (r *Renderer) getPlotWidth() int {
+	tRange := r.cfg.App.TimeRangeSeconds
+	return tRange*r.cfg.App.BarWidth + (tRange-1)*r.cfg.App.BarSpacing
+}
+
+// Renders widgets as a column of plots, the same way as the overlay does
+func (r *Renderer) Render(widgets ...*plot.Widget) *image.RGBA {
+	width := padding*2 + r.getPlotWidth()
+	height := spacing + (spacing+r.cfg.App.PlotHeight)*len(widgets)
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(
+		dst, dst.Bounds(),
+		newUniform(r.cfg.App.Theme.Window.Active.Background),
+		image.Point{}, draw.Src,
+	)
+
+	pos := image.Pt(padding, spacing)
+	for _, widget := range widgets {
+		widget.
+			SetBarSize(r.cfg.App.BarWidth, r.cfg.App.BarSpacing).
+			SetFlags(plot.FlagsAutoKeepMinMax, false).
+			SetDefaultSampleInterval(
+				time.Duration(r.cfg.App.UpdateRateSeconds) * time.Second,
+			).
+			SetStyle(r.plotStyle)
+
+		rect := image.Rectangle{pos, pos.Add(image.Pt(r.getPlotWidth(), r.cfg.App.PlotHeight))}
+		r.DrawPlot(dst, rect, widget)
+		pos.Y += r.cfg.App.PlotHeight + spacing
+	}
+
+	return dst
+}
+
+// Renders widgets and encodes result as PNG
+func (r *Renderer) RenderPNG(out io.Writer, widgets ...*plot.Widget) error {
+	err := png.Encode(out, r.Render(widgets...))
+	if err != nil {
+		return fmt.Errorf("failed to encode png: %w", err)
+	}
+	return nil
+}
+
+// Draws widget into rect of dst
+func (r *Renderer) DrawPlot(dst *image.RGBA, rect image.Rectangle, widget *plot.Widget) {
+	widget.SetSize(rect.Dx(), rect.Dy())
+	theme := r.cfg.App.Theme.Plot
+	offset := rect.Min
+
+	borderColor := theme.Border
+	if level, exceeded := widget.GetExceededLevel(); exceeded {
+		borderColor = theme.ThresholdColor(level == plot.ThresholdCritical)
+	}
+	for _, border := range widget.GetBorders() {
+		if border == nil {
+			continue
+		}
+		fillRect(dst, border.Add(offset), borderColor)
+	}
+
+	fillRect(dst, widget.GetPlotMidLine().Add(offset), theme.Midline)
+
+	for _, line := range widget.GetGridLines() {
+		fillRect(dst, line.Add(offset), theme.Grid)
+	}
+	for _, tick := range widget.GetTimeTicks() {
+		fillRect(dst, tick.Add(offset), theme.Tick)
+	}
+	for _, line := range widget.GetThresholdLines() {
+		fillRect(
+			dst, line.Rect.Add(offset),
+			theme.ThresholdColor(line.Level == plot.ThresholdCritical),
+		)
+	}
+
+	for _, polygon := range widget.GetEnvelope() {
+		fillPolygon(dst, offset, polygon, theme.Envelope)
+	}
+
+	multiSeries := len(widget.GetSeries()) > 1
+
+	for idx, wSeries := range widget.GetSeries() {
+		barColor, lineColor, fillColor := theme.Bar, theme.Line, theme.Fill
+		if multiSeries {
+			barColor, lineColor, fillColor = theme.SeriesColors(idx)
+		}
+
+		for _, polygon := range widget.GetPolygons(idx) {
+			if widget.Style == plot.StyleGradientArea {
+				fillPolygonGradient(
+					dst, offset, polygon, fillColor, widget.GetGradientRatio,
+				)
+			} else {
+				fillPolygon(dst, offset, polygon, fillColor)
+			}
+		}
+
+		for _, line := range widget.GetPolylines(idx) {
+			strokePolyline(dst, offset, line, lineColor)
+		}
+
+		if widget.Style != plot.StyleBar {
+			continue
+		}
+		for x := range wSeries.Data {
+			barRect := widget.GetValueRect(idx, x).Canon()
+			if !barRect.Empty() {
+				fillRect(dst, barRect.Add(offset), barColor)
+			}
+		}
+	}
+
+	for _, overlay := range widget.GetOverlayLines() {
+		strokePolyline(
+			dst, offset, overlay.Line,
+			theme.OverlayColor(overlay.Kind == plot.OverlayP95),
+		)
+	}
+
+	for _, label := range widget.GetLabels(r.textWidth, r.lineHeight()) {
+		labelColor := theme.LabelText
+		if multiSeries && label.SeriesIdx >= 0 {
+			labelColor, _, _ = theme.SeriesColors(label.SeriesIdx)
+		}
+		if label.Error {
+			labelColor = theme.Critical
+		}
+		pos := label.Pos.Add(offset)
+		fillRect(
+			dst,
+			image.Rectangle{pos, pos.Add(image.Pt(r.textWidth(label.Text), r.lineHeight()))},
+			theme.LabelBackground,
+		)
+		r.drawText(dst, pos, label.Text, labelColor)
+	}
+}
@@ -0,0 +1,128 @@
+package raster
+
+import (
+	"bytes"
+	"flag"
+	"image"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"n4/gui-test/pkg/config"
+	"n4/gui-test/pkg/plot"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden images in testdata")
+
+func testData(size int, fn func(x float64) float64) plot.WidgetData {
+	data := make(plot.WidgetData, size)
+	for x := range data {
+		data[x] = fn(float64(x))
+	}
+	return data
+}
+
+func newTestWidgets(size int) []*plot.Widget {
+	format := func(value float64) string {
+		return strconv.FormatFloat(value, 'f', 0, 64)
+	}
+	sine := testData(size, func(x float64) float64 { return 50 + 40*math.Sin(x/8) })
+	cosine := testData(size, func(x float64) float64 { return 30 + 20*math.Cos(x/5) })
+	return []*plot.Widget{
+		plot.NewWidget("Sine", sine).
+			SetLimits(0, 100).
+			SetFormatCallback(format).
+			SetGridStepCallback(plot.GridStepPercent),
+		plot.NewWidget("Stack", nil).
+			SetSeries(
+				plot.WidgetSeries{Label: "A", Data: sine},
+				plot.WidgetSeries{Label: "B", Data: cosine},
+			).
+			SetMode(plot.ModeStacked).
+			SetFormatCallback(format),
+		plot.NewWidget("Mirror", nil).
+			SetSeries(
+				plot.WidgetSeries{Label: "RX", Data: sine},
+				plot.WidgetSeries{Label: "TX", Data: cosine},
+			).
+			SetMode(plot.ModeMirrored).
+			SetFormatCallback(format),
+	}
+}
+
+func assertGolden(t *testing.T, name string, img *image.RGBA) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+
+	path := filepath.Join("testdata", name+".png")
+	if *updateGolden {
+		require.NoError(t, os.MkdirAll("testdata", 0o755))
+		require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o600))
+		return
+	}
+
+	wantRaw, err := os.ReadFile(path)
+	require.NoError(t, err, "golden image is missing, run tests with -update")
+	want, err := png.Decode(bytes.NewReader(wantRaw))
+	require.NoError(t, err)
+	// NOTE: Compare decoded images, PNG stores non-premultiplied colors
+	got, err := png.Decode(&buf)
+	require.NoError(t, err)
+
+	require.Equal(t, want.Bounds(), got.Bounds())
+	for y := got.Bounds().Min.Y; y < got.Bounds().Max.Y; y++ {
+		for x := got.Bounds().Min.X; x < got.Bounds().Max.X; x++ {
+			wr, wg, wb, wa := want.At(x, y).RGBA()
+			gr, gg, gb, ga := got.At(x, y).RGBA()
+			if wr != gr || wg != gg || wb != gb || wa != ga {
+				t.Fatalf("pixel (%d, %d) differs from %s", x, y, path)
+			}
+		}
+	}
+}
+
+func TestRenderer_Render(t *testing.T) {
+	for _, style := range []plot.Style{
+		plot.StyleBar,
+		plot.StyleLine,
+		plot.StyleArea,
+		plot.StyleStep,
+		plot.StyleGradientArea,
+	} {
+		t.Run(style.String(), func(t *testing.T) {
+			cfg := config.NewConfig("")
+			cfg.App.TimeRangeSeconds = 60
+			cfg.App.BarWidth = 2
+			cfg.App.PlotHeight = 40
+			cfg.App.PlotStyle = style.String()
+
+			renderer, err := NewRenderer(zaptest.NewLogger(t), cfg)
+			require.NoError(t, err)
+
+			img := renderer.Render(newTestWidgets(cfg.App.TimeRangeSeconds)...)
+			require.Equal(t, image.Rect(0, 0, 124, 128), img.Bounds())
+			assertGolden(t, "render_"+style.String(), img)
+		})
+	}
+}
+
+func TestNewRenderer_InvalidStyle(t *testing.T) {
+	cfg := config.NewConfig("")
+	cfg.App.PlotStyle = "pie"
+	core, logs := observer.New(zap.WarnLevel)
+	renderer, err := NewRenderer(zap.New(core), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, plot.StyleBar, renderer.plotStyle)
+	assert.Equal(t, 1, logs.FilterMessage("fallback to default plot style").Len())
+}
@@ -0,0 +1,52 @@
+package raster
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"n4/gui-test/resources/fonts"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// TODO: Implement setting of font face and size in config
+func newFontFace() (font.Face, error) {
+	fnt, err := opentype.Parse(fonts.UbuntuSansMedium)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse font: %w", err)
+	}
+	face, err := opentype.NewFace(fnt, &opentype.FaceOptions{
+		Size:    10,
+		DPI:     72,
+		Hinting: font.HintingNone,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create font face: %w", err)
+	}
+	return face, nil
+}
+
+func (r *Renderer) textWidth(str string) int {
+	return font.MeasureString(r.face, str).Round()
+}
+
+func (r *Renderer) lineHeight() int {
+	return r.face.Metrics().Height.Ceil()
+}
+
+// Draws text with pos as top left corner of the line
+func (r *Renderer) drawText(dst *image.RGBA, pos image.Point, str string, clr color.RGBA) {
+	drawer := font.Drawer{
+		Dst:  dst,
+		Src:  newUniform(clr),
+		Face: r.face,
+		Dot: fixed.Point26_6{
+			X: fixed.I(pos.X),
+			Y: fixed.I(pos.Y) + r.face.Metrics().Ascent,
+		},
+	}
+	drawer.DrawString(str)
+}
@@ -0,0 +1,159 @@
+package tui
+
+import (
+	"image"
+	"image/color"
+	"strings"
+)
+
+const (
+	// NOTE: Braille cell is a 2x4 dot matrix
+	dotsX = 2
+	dotsY = 4
+
+	brailleBase = 0x2800
+)
+
+// Bits of braille pattern for dot at [x][y] inside a cell
+var brailleBits = [dotsX][dotsY]uint8{
+	{0x01, 0x02, 0x04, 0x40},
+	{0x08, 0x10, 0x20, 0x80},
+}
+
+type cell struct {
+	dots  uint8
+	text  rune
+	color color.RGBA
+}
+
+// Grid of terminal cells. Dots are set in braille resolution, text
+// replaces dots of a cell
+type canvas struct {
+	cols, rows int
+	cells      []cell
+}
+
+func newCanvas(cols, rows int) *canvas {
+	return &canvas{
+		cols:  cols,
+		rows:  rows,
+		cells: make([]cell, cols*rows),
+	}
+}
+
+func (c *canvas) dotBounds() image.Rectangle {
+	return image.Rect(0, 0, c.cols*dotsX, c.rows*dotsY)
+}
+
+func (c *canvas) setDot(pt image.Point, clr color.RGBA) {
+	if !pt.In(c.dotBounds()) {
+		return
+	}
+	cl := &c.cells[pt.Y/dotsY*c.cols+pt.X/dotsX]
+	cl.dots |= brailleBits[pt.X%dotsX][pt.Y%dotsY]
+	cl.color = clr
+}
+
+func (c *canvas) fillRect(rect image.Rectangle, clr color.RGBA) {
+	rect = rect.Canon().Intersect(c.dotBounds())
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			c.setDot(image.Pt(x, y), clr)
+		}
+	}
+}
+
+// Draws line between dots a and b using Bresenham's algorithm
+func (c *canvas) drawLine(a, b image.Point, clr color.RGBA) {
+	dx, dy := abs(b.X-a.X), -abs(b.Y-a.Y)
+	sx, sy := sign(b.X-a.X), sign(b.Y-a.Y)
+	err := dx + dy
+	for {
+		c.setDot(a, clr)
+		if a == b {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			a.X += sx
+		}
+		if e2 <= dx {
+			err += dx
+			a.Y += sy
+		}
+	}
+}
+
+func (c *canvas) drawPolyline(line []image.Point, clr color.RGBA) {
+	for idx, pt := range line {
+		if idx == 0 {
+			c.setDot(pt, clr)
+			continue
+		}
+		c.drawLine(line[idx-1], pt, clr)
+	}
+}
+
+// Draws text starting from cell pos. Text outside of canvas is cut
+func (c *canvas) drawText(pos image.Point, text string, clr color.RGBA) {
+	if pos.Y < 0 || pos.Y >= c.rows {
+		return
+	}
+	x := pos.X
+	for _, r := range text {
+		if x >= 0 && x < c.cols {
+			cl := &c.cells[pos.Y*c.cols+x]
+			cl.text = r
+			cl.color = clr
+		}
+		x++
+	}
+}
+
+// Returns canvas rows with color escape sequences
+func (c *canvas) lines(colors ColorMode) []string {
+	lines := make([]string, c.rows)
+	for y := range c.rows {
+		var sb strings.Builder
+		var lastColor *color.RGBA
+		for x := range c.cols {
+			cl := c.cells[y*c.cols+x]
+			r := cl.text
+			if r == 0 {
+				if cl.dots == 0 {
+					r = ' '
+				} else {
+					r = rune(brailleBase + int(cl.dots))
+				}
+			}
+			if r != ' ' && (lastColor == nil || *lastColor != cl.color) {
+				sb.WriteString(colors.escape(cl.color))
+				lastColor = &cl.color
+			}
+			sb.WriteRune(r)
+		}
+		if lastColor != nil {
+			sb.WriteString(escReset)
+		}
+		lines[y] = sb.String()
+	}
+	return lines
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func sign(v int) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
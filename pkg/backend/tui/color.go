@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"fmt"
+	"image/color"
+	"os"
+)
+
+const escReset = "\x1b[0m"
+
+type ColorMode int
+
+const (
+	Color256 ColorMode = iota
+	ColorTrue
+)
+
+// Returns truecolor mode if terminal advertises it via COLORTERM
+func DetectColorMode() ColorMode {
+	switch os.Getenv("COLORTERM") {
+	case "truecolor", "24bit":
+		return ColorTrue
+	}
+	return Color256
+}
+
+// Returns escape sequence setting foreground color. Alpha is ignored since
+// terminal background is unknown
+func (m ColorMode) escape(clr color.RGBA) string {
+	if m == ColorTrue {
+		return fmt.Sprintf("\x1b[38;2;%d;%d;%dm", clr.R, clr.G, clr.B)
+	}
+	return fmt.Sprintf("\x1b[38;5;%dm", rgbTo256(clr))
+}
+
+var cubeLevels = [6]int{0, 95, 135, 175, 215, 255}
+
+func nearestCubeLevel(v uint8) int {
+	best := 0
+	for idx, level := range cubeLevels {
+		if abs(level-int(v)) < abs(cubeLevels[best]-int(v)) {
+			best = idx
+		}
+	}
+	return best
+}
+
+func sqDist(r, g, b int, clr color.RGBA) int {
+	dr, dg, db := r-int(clr.R), g-int(clr.G), b-int(clr.B)
+	return dr*dr + dg*dg + db*db
+}
+
+// Returns nearest color of xterm 256 palette from 6x6x6 cube or grayscale
+// ramp. First 16 colors are skipped since terminals redefine them
+func rgbTo256(clr color.RGBA) int {
+	r, g, b := nearestCubeLevel(clr.R), nearestCubeLevel(clr.G), nearestCubeLevel(clr.B)
+	cubeIdx := 16 + 36*r + 6*g + b
+	cubeDist := sqDist(cubeLevels[r], cubeLevels[g], cubeLevels[b], clr)
+
+	avg := (int(clr.R) + int(clr.G) + int(clr.B)) / 3
+	grayIdx := min(max((avg-8+5)/10, 0), 23)
+	grayLevel := 8 + grayIdx*10
+	grayDist := sqDist(grayLevel, grayLevel, grayLevel, clr)
+
+	if grayDist < cubeDist {
+		return 232 + grayIdx
+	}
+	return cubeIdx
+}
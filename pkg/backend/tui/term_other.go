@@ -0,0 +1,18 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd || windows)
+
+package tui
+
+import (
+	"errors"
+	"os"
+)
+
+var errUnsupported = errors.New("terminal is not supported on this platform")
+
+func makeRaw(_, _ *os.File) (restore func() error, err error) {
+	return nil, errUnsupported
+}
+
+func getSize(_ *os.File) (width, height int, err error) {
+	return 0, 0, errUnsupported
+}
@@ -0,0 +1,53 @@
+package tui
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+func makeRaw(in, out *os.File) (restore func() error, err error) {
+	inHandle, outHandle := windows.Handle(in.Fd()), windows.Handle(out.Fd())
+
+	var inMode, outMode uint32
+	err = windows.GetConsoleMode(inHandle, &inMode)
+	if err != nil {
+		return nil, err
+	}
+	err = windows.GetConsoleMode(outHandle, &outMode)
+	if err != nil {
+		return nil, err
+	}
+
+	rawIn := inMode&^(windows.ENABLE_ECHO_INPUT|
+		windows.ENABLE_LINE_INPUT|
+		windows.ENABLE_PROCESSED_INPUT) |
+		windows.ENABLE_VIRTUAL_TERMINAL_INPUT
+	err = windows.SetConsoleMode(inHandle, rawIn)
+	if err != nil {
+		return nil, err
+	}
+	err = windows.SetConsoleMode(outHandle, outMode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING)
+	if err != nil {
+		windows.SetConsoleMode(inHandle, inMode)
+		return nil, err
+	}
+
+	return func() error {
+		err := windows.SetConsoleMode(inHandle, inMode)
+		if err != nil {
+			return err
+		}
+		return windows.SetConsoleMode(outHandle, outMode)
+	}, nil
+}
+
+func getSize(out *os.File) (width, height int, err error) {
+	var info windows.ConsoleScreenBufferInfo
+	err = windows.GetConsoleScreenBufferInfo(windows.Handle(out.Fd()), &info)
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(info.Window.Right-info.Window.Left) + 1,
+		int(info.Window.Bottom-info.Window.Top) + 1, nil
+}
@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	escAltScreenOn  = "\x1b[?1049h"
+	escAltScreenOff = "\x1b[?1049l"
+	escCursorHide   = "\x1b[?25l"
+	escCursorShow   = "\x1b[?25h"
+	escWrapOff      = "\x1b[?7l"
+	escWrapOn       = "\x1b[?7h"
+	escHome         = "\x1b[H"
+	escClearLine    = "\x1b[K"
+	escClearDown    = "\x1b[J"
+
+	KeyCtrlC = 0x03
+)
+
+// Terminal in raw mode with alternate screen
+type Terminal struct {
+	in, out *os.File
+	restore func() error
+}
+
+// Switches stdin/stdout terminal to raw mode and alternate screen. Close
+// must be called to restore the previous state
+func OpenTerminal() (*Terminal, error) {
+	t := &Terminal{
+		in:  os.Stdin,
+		out: os.Stdout,
+	}
+	restore, err := makeRaw(t.in, t.out)
+	if err != nil {
+		return nil, fmt.Errorf("failed to switch terminal to raw mode: %w", err)
+	}
+	t.restore = restore
+
+	_, err = t.out.WriteString(escAltScreenOn + escCursorHide + escWrapOff)
+	if err != nil {
+		restore()
+		return nil, fmt.Errorf("failed to init terminal: %w", err)
+	}
+	return t, nil
+}
+
+func (t *Terminal) Close() error {
+	t.out.WriteString(escReset + escWrapOn + escCursorShow + escAltScreenOff)
+	return t.restore()
+}
+
+// Returns terminal size in cells
+func (t *Terminal) Size() (width, height int, err error) {
+	return getSize(t.out)
+}
+
+// Returns channel of pressed keys. Channel is closed when input ends
+func (t *Terminal) Keys() <-chan byte {
+	keys := make(chan byte)
+	go func() {
+		defer close(keys)
+		buf := make([]byte, 16)
+		for {
+			n, err := t.in.Read(buf)
+			if err != nil {
+				return
+			}
+			for _, key := range buf[:n] {
+				keys <- key
+			}
+		}
+	}()
+	return keys
+}
+
+// Redraws the screen with lines cut to height
+func (t *Terminal) Draw(lines []string, height int) error {
+	lines = lines[:min(len(lines), max(height, 0))]
+
+	var sb strings.Builder
+	sb.WriteString(escHome)
+	for idx, line := range lines {
+		if idx > 0 {
+			sb.WriteString("\r\n")
+		}
+		sb.WriteString(line)
+		sb.WriteString(escClearLine)
+	}
+	sb.WriteString(escClearDown)
+
+	_, err := t.out.WriteString(sb.String())
+	return err
+}
@@ -0,0 +1,300 @@
+package tui
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"n4/gui-test/pkg/config"
+	"n4/gui-test/pkg/plot"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// Terminal rows of a braille plot
+	plotRows = 4
+
+	header = "Stats  s: settings  m: mode  q: quit"
+)
+
+// Keys toggling graphs in settings. Letters used by other shortcuts are
+// skipped
+const SettingsKeys = "1234567890abcdefghijklnoprtuvwxyz"
+
+// Keys switching settings pages when there are more graphs than keys
+const (
+	KeyPrevPage = '<'
+	KeyNextPage = '>'
+)
+
+type Mode int
+
+const (
+	ModeBraille Mode = iota
+	ModeSparkline
+)
+
+var modeNames = []string{
+	ModeBraille:   "braille",
+	ModeSparkline: "sparkline",
+}
+
+func (m Mode) String() string {
+	if m < 0 || int(m) >= len(modeNames) {
+		return fmt.Sprintf("Mode(%d)", int(m))
+	}
+	return modeNames[m]
+}
+
+// Returns the mode following m, wrapping around after the last one
+func (m Mode) Next() Mode {
+	return (m + 1) % Mode(len(modeNames))
+}
+
+func ParseMode(name string) (Mode, error) {
+	idx := slices.Index(modeNames, name)
+	if idx < 0 {
+		return ModeBraille, fmt.Errorf("unknown tui mode: %q", name)
+	}
+	return Mode(idx), nil
+}
+
+// Graph entry of the settings panel
+type SettingsItem struct {
+	Key         rune
+	Label       string
+	Description string
+	Active      bool
+}
+
+// Settings panel showing a page of graph entries at a time
+type SettingsPanel struct {
+	Items []SettingsItem
+	// Zero-based page shown and total number of pages
+	Page, Pages int
+}
+
+// Renders plot widgets as terminal text with color escape sequences
+type Renderer struct {
+	cfg    *config.Config
+	colors ColorMode
+
+	Mode      Mode
+	plotStyle plot.Style
+}
+
+// Returns renderer of cfg. Unknown plot style falls back to the default one
+// with a warning, the same way as the overlay does
+func NewRenderer(logger *zap.Logger, cfg *config.Config, mode Mode, colors ColorMode) *Renderer {
+	plotStyle, err := plot.ParseStyle(cfg.App.PlotStyle)
+	if err != nil {
+		logger.Warn("fallback to default plot style", zap.Error(err))
+	}
+	return &Renderer{
+		cfg:    cfg,
+		colors: colors,
+
+		Mode:      mode,
+		plotStyle: plotStyle,
+	}
+}
+
+// Returns frame lines: header, settings panel if it has items and plots.
+// Plots are cut to fit width columns
+func (r *Renderer) Render(width int, settings SettingsPanel, widgets ...*plot.Widget) []string {
+	lines := []string{r.colors.escape(r.cfg.App.Theme.Plot.LabelText) + header + escReset}
+	lines = append(lines, r.RenderSettings(settings)...)
+	for _, widget := range widgets {
+		if r.Mode == ModeSparkline {
+			lines = append(lines, r.RenderSparkline(width, widget)...)
+		} else {
+			lines = append(lines, r.RenderPlot(width, widget)...)
+		}
+	}
+	return lines
+}
+
+// Returns lines of settings items, followed by page line if there are more
+// pages
+func (r *Renderer) RenderSettings(panel SettingsPanel) []string {
+	lines := make([]string, 0, len(panel.Items)+1)
+	theme := r.cfg.App.Theme.Plot
+	for _, item := range panel.Items {
+		state, clr := "off", theme.Border
+		if item.Active {
+			state, clr = "on ", theme.LabelText
+		}
+		lines = append(lines, fmt.Sprintf("%s%c [%s] %s: %s%s",
+			r.colors.escape(clr), item.Key, state, item.Label, item.Description, escReset,
+		))
+	}
+	if len(panel.Items) > 0 && panel.Pages > 1 {
+		lines = append(lines, fmt.Sprintf("%spage %d/%d  %c %c: switch page%s",
+			r.colors.escape(theme.LabelText), panel.Page+1, panel.Pages,
+			KeyPrevPage, KeyNextPage, escReset,
+		))
+	}
+	return lines
+}
+
+// Returns number of samples fitting width columns of dots
+func (r *Renderer) getSamples(width, dotsPerCol int) int {
+	return max(min(r.cfg.App.TimeRangeSeconds, width*dotsPerCol), 0)
+}
+
+// Keeps only latest samples of each series and overlays
+func trimSeries(widget *plot.Widget, samples int) {
+	wSeries := slices.Clone(widget.GetSeries())
+	for idx := range wSeries {
+		// NOTE: The latest value is the first one
+		data := wSeries[idx].Data
+		wSeries[idx].Data = data[:min(samples, len(data))]
+	}
+	widget.SetSeries(wSeries...)
+	widget.SetOverlays(widget.GetOverlays().Head(samples))
+}
+
+// Renders widget as braille plot with labels laid out like in the overlay
+func (r *Renderer) RenderPlot(width int, widget *plot.Widget) []string {
+	samples := r.getSamples(width, dotsX)
+	cols := (samples + dotsX - 1) / dotsX
+	if cols == 0 {
+		return nil
+	}
+	trimSeries(widget, samples)
+
+	widget.
+		SetSize(cols*dotsX, plotRows*dotsY).
+		SetBarSize(1, 0).
+		SetFlags(plot.FlagsAutoKeepMinMax, false).
+		ClearFlags(plot.FlagsBorderAll | plot.FlagsGridAll).
+		SetDefaultSampleInterval(
+			time.Duration(r.cfg.App.UpdateRateSeconds) * time.Second,
+		).
+		SetStyle(r.plotStyle)
+	widget.LabelPadding = image.Pt(dotsX, 0)
+
+	theme := r.cfg.App.Theme.Plot
+	cnv := newCanvas(cols, plotRows)
+
+	cnv.fillRect(widget.GetPlotMidLine(), theme.Midline)
+	for _, line := range widget.GetThresholdLines() {
+		cnv.fillRect(line.Rect, theme.ThresholdColor(line.Level == plot.ThresholdCritical))
+	}
+
+	// NOTE: Envelope is drawn as its outline, filled area would hide the
+	// plot under it
+	for _, polygon := range widget.GetEnvelope() {
+		cnv.drawPolyline(append(polygon, polygon[0]), theme.Envelope)
+	}
+
+	multiSeries := len(widget.GetSeries()) > 1
+	for idx, wSeries := range widget.GetSeries() {
+		barColor, lineColor, fillColor := theme.Bar, theme.Line, theme.Fill
+		if multiSeries {
+			barColor, lineColor, fillColor = theme.SeriesColors(idx)
+		}
+
+		// NOTE: Filled areas are drawn as one dot wide bars, terminal has
+		// no anti-aliasing to benefit from polygons
+		if widget.Style == plot.StyleBar || widget.Style.HasFill() {
+			areaColor := barColor
+			if widget.Style.HasFill() {
+				areaColor = fillColor
+			}
+			for x := range wSeries.Data {
+				cnv.fillRect(widget.GetValueRect(idx, x), areaColor)
+			}
+		}
+		for _, line := range widget.GetPolylines(idx) {
+			cnv.drawPolyline(line, lineColor)
+		}
+	}
+
+	for _, overlay := range widget.GetOverlayLines() {
+		cnv.drawPolyline(overlay.Line, theme.OverlayColor(overlay.Kind == plot.OverlayP95))
+	}
+
+	textWidth := func(text string) int {
+		return utf8.RuneCountInString(text) * dotsX
+	}
+	for _, label := range widget.GetLabels(textWidth, dotsY) {
+		labelColor := theme.LabelText
+		if multiSeries && label.SeriesIdx >= 0 {
+			labelColor, _, _ = theme.SeriesColors(label.SeriesIdx)
+		}
+		if label.Error {
+			labelColor = theme.Critical
+		}
+		pos := image.Pt(label.Pos.X/dotsX, label.Pos.Y/dotsY)
+		cnv.drawText(pos, label.Text, labelColor)
+	}
+
+	return cnv.lines(r.colors)
+}
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Renders widget as one line per series: name, value, sparkline and min/max
+func (r *Renderer) RenderSparkline(width int, widget *plot.Widget) []string {
+	theme := r.cfg.App.Theme.Plot
+	xMin, xMax := widget.GetSanitizedMinMax()
+	rangeText := fmt.Sprintf(" %s..%s",
+		widget.FormatCallback(xMin), widget.FormatCallback(xMax),
+	)
+
+	valueColor := theme.LabelText
+	if level, exceeded := widget.GetExceededLevel(); exceeded {
+		valueColor = theme.ThresholdColor(level == plot.ThresholdCritical)
+	}
+
+	wSeries := widget.GetSeries()
+	multiSeries := len(wSeries) > 1
+	labelWidth := 0
+	for _, s := range wSeries {
+		labelWidth = max(labelWidth, utf8.RuneCountInString(s.Label))
+	}
+
+	lines := make([]string, 0, len(wSeries))
+	for idx, s := range wSeries {
+		barColor := theme.Bar
+		if multiSeries {
+			barColor, _, _ = theme.SeriesColors(idx)
+		}
+		value := "-"
+		if len(s.Data) > 0 {
+			value = widget.FormatCallback(s.Data[0])
+		}
+		label := fmt.Sprintf("%-*s ", labelWidth, s.Label)
+		value = fmt.Sprintf("%10s ", value)
+		prefix := label + value
+
+		samples := r.getSamples(
+			width-utf8.RuneCountInString(prefix)-utf8.RuneCountInString(rangeText), 1,
+		)
+		var spark strings.Builder
+		// NOTE: The latest value is the first one, draw it on the right
+		for x := min(samples, len(s.Data)) - 1; x >= 0; x-- {
+			val := s.Data[x]
+			if math.IsNaN(val) || math.IsInf(val, 0) || xMax <= xMin {
+				spark.WriteRune(' ')
+				continue
+			}
+			level := int((val - xMin) / (xMax - xMin) * float64(len(sparkBlocks)-1))
+			level = max(min(level, len(sparkBlocks)-1), 0)
+			spark.WriteRune(sparkBlocks[level])
+		}
+
+		lines = append(lines, r.colors.escape(theme.LabelText)+label+
+			r.colors.escape(valueColor)+value+
+			r.colors.escape(barColor)+spark.String()+
+			r.colors.escape(theme.LabelText)+rangeText+escReset,
+		)
+	}
+	return lines
+}
@@ -0,0 +1,216 @@
+package tui
+
+import (
+	"image"
+	"image/color"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"n4/gui-test/pkg/config"
+	"n4/gui-test/pkg/plot"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+var escapeRe = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func stripEscapes(lines []string) []string {
+	stripped := make([]string, len(lines))
+	for idx, line := range lines {
+		stripped[idx] = escapeRe.ReplaceAllString(line, "")
+	}
+	return stripped
+}
+
+func newTestRenderer(t *testing.T, style string, mode Mode) *Renderer {
+	cfg := config.NewConfig("")
+	cfg.App.TimeRangeSeconds = 8
+	cfg.App.PlotStyle = style
+	return NewRenderer(zaptest.NewLogger(t), cfg, mode, Color256)
+}
+
+func newTestWidget(data plot.WidgetData) *plot.Widget {
+	return plot.NewWidget("cpu", data).
+		SetLimits(0, 8).
+		SetFlags(plot.FlagsLabelsAll|plot.FlagsReverseOrder, true).
+		SetFormatCallback(func(value float64) string {
+			return strconv.FormatFloat(value, 'f', 0, 64)
+		})
+}
+
+func TestCanvas_SetDot(t *testing.T) {
+	c := newCanvas(2, 1)
+	c.setDot(image.Pt(0, 0), color.RGBA{})
+	c.setDot(image.Pt(1, 3), color.RGBA{})
+	c.setDot(image.Pt(3, 1), color.RGBA{})
+	c.setDot(image.Pt(4, 0), color.RGBA{}) // NOTE: Outside of canvas
+	assert.Equal(t, []string{"⢁⠐"}, stripEscapes(c.lines(Color256)))
+}
+
+func TestCanvas_DrawLine(t *testing.T) {
+	c := newCanvas(2, 1)
+	c.drawLine(image.Pt(0, 3), image.Pt(3, 0), color.RGBA{})
+	assert.Equal(t, []string{"⡠⠊"}, stripEscapes(c.lines(Color256)))
+}
+
+func TestRgbTo256(t *testing.T) {
+	tests := []struct {
+		clr  color.RGBA
+		want int
+	}{
+		{color.RGBA{0, 0, 0, 255}, 16},
+		{color.RGBA{255, 255, 255, 255}, 231},
+		{color.RGBA{255, 0, 0, 255}, 196},
+		{color.RGBA{128, 128, 128, 255}, 244},
+		{color.RGBA{250, 0, 0, 205}, 196},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, rgbTo256(tt.clr), "%v", tt.clr)
+	}
+}
+
+func TestRenderer_RenderSettings(t *testing.T) {
+	r := newTestRenderer(t, "bar", ModeBraille)
+	items := []SettingsItem{
+		{Key: '1', Label: "Mem", Description: "used", Active: true},
+		{Key: '2', Label: "Swap", Description: "used"},
+	}
+
+	lines := stripEscapes(r.RenderSettings(SettingsPanel{Items: items, Pages: 1}))
+	assert.Equal(t, []string{"1 [on ] Mem: used", "2 [off] Swap: used"}, lines)
+
+	lines = stripEscapes(r.RenderSettings(SettingsPanel{Items: items, Page: 1, Pages: 3}))
+	assert.Equal(t, "page 2/3  < >: switch page", lines[len(lines)-1])
+
+	assert.Empty(t, r.RenderSettings(SettingsPanel{Pages: 3}))
+}
+
+func TestRenderer_RenderPlot(t *testing.T) {
+	tests := []struct {
+		name   string
+		style  string
+		tRange int
+		labels bool
+		want   []string
+	}{
+		{
+			name:   "Bar",
+			style:  "bar",
+			tRange: 8,
+			want: []string{
+				"   ⢸",
+				"⣇ ⢀⣿",
+				"⣿⡀⣸⣿",
+				"⣿⣇⣿⣿",
+			},
+		},
+		{
+			name:   "Line",
+			style:  "line",
+			tRange: 8,
+			want: []string{
+				"   ⡸",
+				"⢣ ⢠⠃",
+				"⠈⡆⡜ ",
+				"⣀⣱⣃⣀",
+			},
+		},
+		{
+			name:   "Step with labels",
+			style:  "step",
+			tRange: 24,
+			labels: true,
+			want: []string{
+				" cpu⡄  ⢸⢧ 8 ",
+				"⢤0 ⡇⠹⣄ ⢸⠈⢧⡀ ",
+				" ⢳⡀⡇ ⠘⣆⢸  ⢳⡀",
+				"⣀⣀⣳⣇⣀⣀⣘⣾⣀⣀0⣳",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestRenderer(t, tt.style, ModeBraille)
+			r.cfg.App.TimeRangeSeconds = tt.tRange
+
+			data := make(plot.WidgetData, tt.tRange)
+			for x := range data {
+				data[x] = float64(x % 9)
+			}
+			if !tt.labels {
+				data = plot.WidgetData{8, 6, 4, 2, 0, 2, 4, 6}
+			}
+			widget := newTestWidget(data)
+			if !tt.labels {
+				widget.ClearFlags(plot.FlagsLabelsAll)
+			}
+
+			got := r.RenderPlot(80, widget)
+			assert.Equal(t, tt.want, stripEscapes(got))
+		})
+	}
+}
+
+func TestRenderer_RenderPlot_Narrow(t *testing.T) {
+	r := newTestRenderer(t, "bar", ModeBraille)
+	widget := newTestWidget(plot.WidgetData{8, 6, 4, 2, 0, 2, 4, 6}).
+		ClearFlags(plot.FlagsLabelsAll)
+
+	// NOTE: Only the latest samples are kept
+	got := r.RenderPlot(2, widget)
+	assert.Equal(t, plot.WidgetData{8, 6, 4, 2}, widget.GetData())
+	assert.Equal(t, []string{
+		" ⢸",
+		"⢀⣿",
+		"⣸⣿",
+		"⣿⣿",
+	}, stripEscapes(got))
+}
+
+func TestRenderer_RenderPlot_Overlays(t *testing.T) {
+	r := newTestRenderer(t, "bar", ModeBraille)
+	widget := newTestWidget(plot.WidgetData{8, 6, 4, 2, 0, 2, 4, 6}).
+		ClearFlags(plot.FlagsLabelsAll).
+		SetOverlays(plot.Overlays{Mean: plot.WidgetData{4, 4, 4, 4, 4, 4, 4, 4}})
+
+	got := r.RenderPlot(2, widget)
+	assert.Equal(t, plot.WidgetData{4, 4, 4, 4}, widget.GetOverlays().Mean)
+	// NOTE: Mean line crosses the bars
+	assert.Equal(t, []string{
+		" ⢸",
+		"⣀⣿",
+		"⣸⣿",
+		"⣿⣿",
+	}, stripEscapes(got))
+}
+
+func TestRenderer_RenderSparkline(t *testing.T) {
+	r := newTestRenderer(t, "bar", ModeSparkline)
+	got := r.RenderSparkline(80, newTestWidget(plot.WidgetData{8, 6, 4, 2, 0, 2, 4, 6}))
+	assert.Equal(t, []string{
+		"cpu          8 ▆▄▂▁▂▄▆█ 0..8",
+	}, stripEscapes(got))
+}
+
+func TestNewRenderer_InvalidStyle(t *testing.T) {
+	cfg := config.NewConfig("")
+	cfg.App.PlotStyle = "pie"
+	core, logs := observer.New(zap.WarnLevel)
+	r := NewRenderer(zap.New(core), cfg, ModeBraille, Color256)
+	assert.Equal(t, plot.StyleBar, r.plotStyle)
+	assert.Equal(t, 1, logs.FilterMessage("fallback to default plot style").Len())
+}
+
+func TestParseMode(t *testing.T) {
+	for _, mode := range []Mode{ModeBraille, ModeSparkline} {
+		got, err := ParseMode(mode.String())
+		assert.NoError(t, err)
+		assert.Equal(t, mode, got)
+	}
+	_, err := ParseMode("ascii")
+	assert.Error(t, err)
+}
@@ -25,14 +25,27 @@ type App struct {
 
 	TimeRangeSeconds  int `koanf:"time_range_seconds"`
 	UpdateRateSeconds int `koanf:"update_rate_seconds"`
+	// Overrides of collection intervals by collector name
+	CollectorIntervalsMs map[string]int `koanf:"collector_intervals_ms"`
 
 	BarSpacing int `koanf:"bar_spacing"`
 	BarWidth   int `koanf:"bar_width"`
 
-	PlotHeight int `koanf:"plot_height"`
+	PlotHeight int    `koanf:"plot_height"`
+	PlotStyle  string `koanf:"plot_style"`
 
 	GraphSettings map[string]*GraphSettings `koanf:"graph_settings"`
 
+	Processes []ProcessTarget `koanf:"processes"`
+
+	TopProcesses TopProcesses `koanf:"top_processes"`
+
+	Ping []PingTarget `koanf:"ping"`
+
+	Derived []DerivedEntry `koanf:"derived"`
+
+	Disks Disks `koanf:"disks"`
+
 	Position image.Point `koanf:"position"`
 
 	Theme Theme `koanf:"theme"`
@@ -40,6 +53,95 @@ type App struct {
 
 type GraphSettings struct {
 	Enabled bool `koanf:"enabled"`
+
+	Overlays GraphOverlays `koanf:"overlays"`
+}
+
+// Statistics of plotted values over a rolling window drawn over the plot
+type GraphOverlays struct {
+	// Lines of rolling mean and 95th percentile, also shown in labels
+	Mean bool `koanf:"mean"`
+	P95  bool `koanf:"p95"`
+	// Envelope between rolling min and max
+	MinMax bool `koanf:"min_max"`
+	// Default is 60
+	WindowSeconds int `koanf:"window_seconds"`
+}
+
+// Process to monitor. Non-empty criteria must all match, the oldest matching
+// process is used
+type ProcessTarget struct {
+	// Used in graph labels and config names
+	Name string `koanf:"name"`
+
+	PID      int32  `koanf:"pid"`
+	Exe      string `koanf:"exe"`
+	ExeRegex string `koanf:"exe_regex"`
+	Cmdline  string `koanf:"cmdline"`
+
+	// Sum stats of the process and all its descendants
+	Tree bool `koanf:"tree"`
+	// Number of the largest children by RSS shown separately in tree mode
+	TopN int `koanf:"top_n"`
+}
+
+// Panel of processes using the most CPU, memory and IO
+type TopProcesses struct {
+	Enabled bool `koanf:"enabled"`
+	// Number of processes shown per metric
+	Count int `koanf:"count"`
+}
+
+// Address to measure latency to
+type PingTarget struct {
+	// Used in graph labels and config names
+	Name string `koanf:"name"`
+
+	// One of icmp, tcp or http
+	Probe string `koanf:"probe"`
+	// Host for icmp, host:port for tcp and URL for http
+	Address string `koanf:"address"`
+	// Probe is lost if not answered in time, default is 1000
+	TimeoutMs int `koanf:"timeout_ms"`
+}
+
+// Graph of expression over other entries, i.e. sys_mem_used / sys_mem_total
+type DerivedEntry struct {
+	// Used in graph labels and config names
+	Name string `koanf:"name"`
+	// Refers to entries by name and supports functions rate(x),
+	// avg_over(x, 30s), max_over(x, 30s), min_over(x, 30s), clamp(x, lo, hi)
+	Expr string `koanf:"expr"`
+}
+
+// Disks shown in graphs and how they are labeled
+type Disks struct {
+	// Disk is shown if it matches include rules, when there are any, and
+	// doesn't match exclude rules
+	Include DiskRules `koanf:"include"`
+	Exclude DiskRules `koanf:"exclude"`
+
+	// Settings of particular disks
+	Mounts []DiskMount `koanf:"mounts"`
+}
+
+// Disk matches rules if it matches any of them
+type DiskRules struct {
+	// Glob patterns, i.e. /snap/*/*. NOTE: * doesn't match path separator
+	Mountpoints []string `koanf:"mountpoints"`
+	Fstypes     []string `koanf:"fstypes"`
+	// Regular expressions of device paths
+	DeviceRegex []string `koanf:"device_regex"`
+}
+
+type DiskMount struct {
+	Mountpoint string `koanf:"mountpoint"`
+	// Shown instead of mountpoint
+	Alias string `koanf:"alias"`
+
+	// Free space thresholds, zero disables threshold
+	WarningFreeGiB  float64 `koanf:"warning_free_gib"`
+	CriticalFreeGiB float64 `koanf:"critical_free_gib"`
 }
 
 type Theme struct {
@@ -58,9 +160,54 @@ type ThemeWindowType struct {
 type ThemePlot struct {
 	Border          color.RGBA `koanf:"border"`
 	Midline         color.RGBA `koanf:"midline"`
+	Grid            color.RGBA `koanf:"grid"`
+	Tick            color.RGBA `koanf:"tick"`
 	Bar             color.RGBA `koanf:"bar"`
+	Line            color.RGBA `koanf:"line"`
+	Fill            color.RGBA `koanf:"fill"`
 	LabelText       color.RGBA `koanf:"label_text"`
 	LabelBackground color.RGBA `koanf:"label_background"`
+
+	// Colors of threshold lines, border takes them while threshold is
+	// exceeded
+	Warning  color.RGBA `koanf:"warning"`
+	Critical color.RGBA `koanf:"critical"`
+
+	// Colors of statistical overlays
+	Mean     color.RGBA `koanf:"mean"`
+	P95      color.RGBA `koanf:"p95"`
+	Envelope color.RGBA `koanf:"envelope"`
+
+	// Colors of series in graphs with multiple series
+	Series []color.RGBA `koanf:"series"`
+}
+
+// Returns color of critical or warning threshold
+func (t *ThemePlot) ThresholdColor(critical bool) color.RGBA {
+	if critical {
+		return t.Critical
+	}
+	return t.Warning
+}
+
+// Returns color of p95 or mean overlay line
+func (t *ThemePlot) OverlayColor(p95 bool) color.RGBA {
+	if p95 {
+		return t.P95
+	}
+	return t.Mean
+}
+
+// Returns bar, line and fill colors of series idx in graphs with multiple
+// series
+func (t *ThemePlot) SeriesColors(idx int) (bar, line, fill color.RGBA) {
+	if len(t.Series) == 0 {
+		return t.Bar, t.Line, t.Fill
+	}
+	clr := t.Series[idx%len(t.Series)]
+	// NOTE: RGBA is alpha-premultiplied, so all channels are scaled
+	fill = color.RGBA{clr.R / 2, clr.G / 2, clr.B / 2, clr.A / 2}
+	return clr, clr, fill
 }
 
 func NewApp() App {
@@ -68,10 +215,34 @@ func NewApp() App {
 		TimeRangeSeconds:  120,
 		UpdateRateSeconds: 1,
 
+		CollectorIntervalsMs: map[string]int{},
+
 		BarSpacing: 0,
 		BarWidth:   1,
 
 		PlotHeight: 30,
+		PlotStyle:  "bar",
+
+		TopProcesses: TopProcesses{
+			Count: 5,
+		},
+
+		Disks: Disks{
+			// NOTE: Pseudo and virtual filesystems of Linux, snap packages
+			// are mounted from loop devices
+			Exclude: DiskRules{
+				Mountpoints: []string{"/snap/*/*", "/proc/*", "/sys/*", "/dev/*"},
+				Fstypes: []string{
+					"autofs", "binfmt_misc", "bpf", "cgroup", "cgroup2",
+					"configfs", "debugfs", "devpts", "devtmpfs", "efivarfs",
+					"fuse.gvfsd-fuse", "fuse.portal", "fusectl", "hugetlbfs",
+					"mqueue", "nsfs", "overlay", "proc", "pstore", "ramfs",
+					"rpc_pipefs", "securityfs", "selinuxfs", "squashfs",
+					"sysfs", "tmpfs", "tracefs",
+				},
+				DeviceRegex: []string{"^/dev/loop"},
+			},
+		},
 
 		Position: image.Pt(10, 10),
 
@@ -87,9 +258,30 @@ func NewApp() App {
 			Plot: ThemePlot{
 				Border:          color.RGBA{150, 100, 100, 205},
 				Midline:         color.RGBA{0, 200, 0, 100},
+				Grid:            color.RGBA{40, 40, 40, 40},
+				Tick:            color.RGBA{40, 40, 40, 40},
 				Bar:             color.RGBA{250, 0, 0, 105},
+				Line:            color.RGBA{250, 0, 0, 205},
+				Fill:            color.RGBA{125, 0, 0, 105},
 				LabelText:       color.RGBA{255, 255, 255, 180},
 				LabelBackground: color.RGBA{0, 0, 0, 0},
+
+				Warning:  color.RGBA{230, 180, 40, 205},
+				Critical: color.RGBA{255, 40, 40, 230},
+
+				Mean:     color.RGBA{60, 150, 230, 205},
+				P95:      color.RGBA{180, 90, 220, 205},
+				Envelope: color.RGBA{40, 40, 60, 60},
+
+				Series: []color.RGBA{
+					{230, 60, 60, 205},
+					{60, 150, 230, 205},
+					{230, 180, 40, 205},
+					{80, 200, 100, 205},
+					{180, 90, 220, 205},
+					{40, 200, 200, 205},
+					{230, 120, 40, 205},
+				},
 			},
 		},
 	}
@@ -17,13 +17,42 @@ var testDefaultApp = App{
 	TimeRangeSeconds:  120,
 	UpdateRateSeconds: 1,
 
+	CollectorIntervalsMs: make(map[string]int),
+
 	BarSpacing: 0,
 	BarWidth:   1,
 
 	PlotHeight: 30,
+	PlotStyle:  "bar",
 
 	GraphSettings: make(map[string]*GraphSettings),
 
+	Processes: []ProcessTarget{},
+
+	TopProcesses: TopProcesses{
+		Count: 5,
+	},
+
+	Ping: []PingTarget{},
+
+	Derived: []DerivedEntry{},
+
+	Disks: Disks{
+		Exclude: DiskRules{
+			Mountpoints: []string{"/snap/*/*", "/proc/*", "/sys/*", "/dev/*"},
+			Fstypes: []string{
+				"autofs", "binfmt_misc", "bpf", "cgroup", "cgroup2",
+				"configfs", "debugfs", "devpts", "devtmpfs", "efivarfs",
+				"fuse.gvfsd-fuse", "fuse.portal", "fusectl", "hugetlbfs",
+				"mqueue", "nsfs", "overlay", "proc", "pstore", "ramfs",
+				"rpc_pipefs", "securityfs", "selinuxfs", "squashfs",
+				"sysfs", "tmpfs", "tracefs",
+			},
+			DeviceRegex: []string{"^/dev/loop"},
+		},
+		Mounts: []DiskMount{},
+	},
+
 	Position: image.Pt(10, 10),
 
 	Theme: Theme{
@@ -38,9 +67,30 @@ var testDefaultApp = App{
 		Plot: ThemePlot{
 			Border:          color.RGBA{150, 100, 100, 205},
 			Midline:         color.RGBA{0, 200, 0, 100},
+			Grid:            color.RGBA{40, 40, 40, 40},
+			Tick:            color.RGBA{40, 40, 40, 40},
 			Bar:             color.RGBA{250, 0, 0, 105},
+			Line:            color.RGBA{250, 0, 0, 205},
+			Fill:            color.RGBA{125, 0, 0, 105},
 			LabelText:       color.RGBA{255, 255, 255, 180},
 			LabelBackground: color.RGBA{0, 0, 0, 0},
+
+			Warning:  color.RGBA{230, 180, 40, 205},
+			Critical: color.RGBA{255, 40, 40, 230},
+
+			Mean:     color.RGBA{60, 150, 230, 205},
+			P95:      color.RGBA{180, 90, 220, 205},
+			Envelope: color.RGBA{40, 40, 60, 60},
+
+			Series: []color.RGBA{
+				{230, 60, 60, 205},
+				{60, 150, 230, 205},
+				{230, 180, 40, 205},
+				{80, 200, 100, 205},
+				{180, 90, 220, 205},
+				{40, 200, 200, 205},
+				{230, 120, 40, 205},
+			},
 		},
 	},
 }
@@ -205,10 +255,35 @@ func TestConfig_Save(t *testing.T) {
 			enable_debug: false
 			time_range_seconds: 120
 			update_rate_seconds: 1
+			collector_intervals_ms: {}
 			bar_spacing: 0
 			bar_width: 1
 			plot_height: 30
+			plot_style: bar
 			graph_settings: {}
+			processes: []
+			top_processes:
+				enabled: false
+				count: 5
+			ping: []
+			derived: []
+			disks:
+				include:
+					mountpoints: []
+					fstypes: []
+					device_regex: []
+				exclude:
+					mountpoints: ["/snap/*/*", "/proc/*", "/sys/*", "/dev/*"]
+					fstypes: [
+						"autofs", "binfmt_misc", "bpf", "cgroup", "cgroup2",
+						"configfs", "debugfs", "devpts", "devtmpfs", "efivarfs",
+						"fuse.gvfsd-fuse", "fuse.portal", "fusectl", "hugetlbfs",
+						"mqueue", "nsfs", "overlay", "proc", "pstore", "ramfs",
+						"rpc_pipefs", "securityfs", "selinuxfs", "squashfs",
+						"sysfs", "tmpfs", "tracefs",
+					]
+					device_regex: ["^/dev/loop"]
+				mounts: []
 			position:
 				X: 10
 				Y: 10
@@ -221,9 +296,26 @@ func TestConfig_Save(t *testing.T) {
 				plot:
 					border: {"R": 150, "G": 100, "B": 100, "A": 205}
 					midline: {"R": 0, "G": 200, "B": 0, "A": 100}
+					grid: {"R": 40, "G": 40, "B": 40, "A": 40}
+					tick: {"R": 40, "G": 40, "B": 40, "A": 40}
 					bar: {"R": 250, "G": 0, "B": 0, "A": 105}
+					line: {"R": 250, "G": 0, "B": 0, "A": 205}
+					fill: {"R": 125, "G": 0, "B": 0, "A": 105}
 					label_text: {"R": 255, "G": 255, "B": 255, "A": 180}
 					label_background: {"R": 0, "G": 0, "B": 0, "A": 0}
+					warning: {"R": 230, "G": 180, "B": 40, "A": 205}
+					critical: {"R": 255, "G": 40, "B": 40, "A": 230}
+					mean: {"R": 60, "G": 150, "B": 230, "A": 205}
+					p95: {"R": 180, "G": 90, "B": 220, "A": 205}
+					envelope: {"R": 40, "G": 40, "B": 60, "A": 60}
+					series:
+						- {"R": 230, "G": 60, "B": 60, "A": 205}
+						- {"R": 60, "G": 150, "B": 230, "A": 205}
+						- {"R": 230, "G": 180, "B": 40, "A": 205}
+						- {"R": 80, "G": 200, "B": 100, "A": 205}
+						- {"R": 180, "G": 90, "B": 220, "A": 205}
+						- {"R": 40, "G": 200, "B": 200, "A": 205}
+						- {"R": 230, "G": 120, "B": 40, "A": 205}
 		`
 
 	tests := []struct {
@@ -0,0 +1,15 @@
+// Package focus resolves process of the currently focused window
+package focus
+
+import "errors"
+
+var (
+	ErrNoActiveWindow = errors.New("no active window")
+	ErrNoPID          = errors.New("active window has no pid")
+	ErrUnsupported    = errors.New("focused window lookup is not supported on this platform")
+)
+
+// Returns PID of the process owning the currently focused window
+func ActivePID() (int32, error) {
+	return activePID()
+}
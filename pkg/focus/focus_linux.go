@@ -0,0 +1,67 @@
+package focus
+
+import (
+	"fmt"
+
+	"github.com/jezek/xgb"
+	"github.com/jezek/xgb/xproto"
+)
+
+// NOTE: Window manager must support EWMH, which is true for most of them
+func activePID() (int32, error) {
+	conn, err := xgb.NewConn()
+	if err != nil {
+		return 0, fmt.Errorf("failed to connect to X server: %w", err)
+	}
+	defer conn.Close()
+
+	root := xproto.Setup(conn).DefaultScreen(conn).Root
+
+	win, err := getCardinal(conn, root, "_NET_ACTIVE_WINDOW", xproto.AtomWindow)
+	if err != nil {
+		return 0, err
+	}
+	if win == 0 {
+		return 0, ErrNoActiveWindow
+	}
+
+	pid, err := getCardinal(conn, xproto.Window(win), "_NET_WM_PID", xproto.AtomCardinal)
+	if err != nil {
+		return 0, err
+	}
+	if pid == 0 {
+		return 0, ErrNoPID
+	}
+
+	return int32(pid), nil
+}
+
+func internAtom(conn *xgb.Conn, name string) (xproto.Atom, error) {
+	reply, err := xproto.InternAtom(conn, true, uint16(len(name)), name).Reply()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get atom %s: %w", name, err)
+	}
+	return reply.Atom, nil
+}
+
+// Returns the first 32 bit value of window property, 0 if it is not set
+func getCardinal(
+	conn *xgb.Conn, win xproto.Window, name string, propType xproto.Atom,
+) (uint32, error) {
+	atom, err := internAtom(conn, name)
+	if err != nil {
+		return 0, err
+	}
+	if atom == xproto.AtomNone {
+		return 0, nil
+	}
+
+	reply, err := xproto.GetProperty(conn, false, win, atom, propType, 0, 1).Reply()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get property %s: %w", name, err)
+	}
+	if reply.Format != 32 || len(reply.Value) < 4 {
+		return 0, nil
+	}
+	return xgb.Get32(reply.Value), nil
+}
@@ -0,0 +1,58 @@
+package focus
+
+import (
+	"os"
+	"testing"
+
+	"github.com/jezek/xgb"
+	"github.com/jezek/xgb/xproto"
+	"github.com/stretchr/testify/require"
+)
+
+// NOTE: Overwrites _NET_ACTIVE_WINDOW, so it's opted in for a throwaway X
+// server only, e.g. `GOVERMON_XVFB_TEST=1 xvfb-run go test ./pkg/focus`.
+// There is no window manager in Xvfb, so the property is set manually
+func TestActivePID(t *testing.T) {
+	if os.Getenv("GOVERMON_XVFB_TEST") != "1" {
+		t.Skip("GOVERMON_XVFB_TEST is not set")
+	}
+
+	conn, err := xgb.NewConn()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	screen := xproto.Setup(conn).DefaultScreen(conn)
+
+	setProp := func(win xproto.Window, name string, propType xproto.Atom, value uint32) {
+		reply, err := xproto.InternAtom(conn, false, uint16(len(name)), name).Reply()
+		require.NoError(t, err)
+		data := make([]byte, 4)
+		xgb.Put32(data, value)
+		err = xproto.ChangePropertyChecked(
+			conn, xproto.PropModeReplace, win, reply.Atom, propType, 32, 1, data,
+		).Check()
+		require.NoError(t, err)
+	}
+
+	win, err := xproto.NewWindowId(conn)
+	require.NoError(t, err)
+	err = xproto.CreateWindowChecked(
+		conn, screen.RootDepth, win, screen.Root, 0, 0, 10, 10, 0,
+		xproto.WindowClassInputOutput, screen.RootVisual, 0, nil,
+	).Check()
+	require.NoError(t, err)
+	defer xproto.DestroyWindow(conn, win)
+
+	setProp(screen.Root, "_NET_ACTIVE_WINDOW", xproto.AtomWindow, 0)
+	_, err = ActivePID()
+	require.ErrorIs(t, err, ErrNoActiveWindow)
+
+	setProp(screen.Root, "_NET_ACTIVE_WINDOW", xproto.AtomWindow, uint32(win))
+	_, err = ActivePID()
+	require.ErrorIs(t, err, ErrNoPID)
+
+	setProp(win, "_NET_WM_PID", xproto.AtomCardinal, uint32(os.Getpid()))
+	pid, err := ActivePID()
+	require.NoError(t, err)
+	require.Equal(t, int32(os.Getpid()), pid)
+}
@@ -0,0 +1,7 @@
+//go:build !linux && !windows
+
+package focus
+
+func activePID() (int32, error) {
+	return 0, ErrUnsupported
+}
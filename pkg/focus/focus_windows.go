@@ -0,0 +1,25 @@
+package focus
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/windows"
+)
+
+func activePID() (int32, error) {
+	hwnd := windows.GetForegroundWindow()
+	if hwnd == 0 {
+		return 0, ErrNoActiveWindow
+	}
+
+	var pid uint32
+	_, err := windows.GetWindowThreadProcessId(hwnd, &pid)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get window process: %w", err)
+	}
+	if pid == 0 {
+		return 0, ErrNoPID
+	}
+
+	return int32(pid), nil
+}
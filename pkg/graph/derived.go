@@ -0,0 +1,32 @@
+package graph
+
+import (
+	"n4/gui-test/pkg/app"
+	"n4/gui-test/pkg/series"
+)
+
+func init() {
+	RegisterGraphs(app.CollectorDerived, Derived)
+}
+
+// Returns graph of expression over other entries, described by expression
+func DerivedEntry(derived *series.DerivedCollector) *Graph {
+	usedSeries := []*series.Entry{&derived.Value}
+	sub := &series.Subscriber{}
+	data := derived.Value.Subscribe(sub)
+
+	setts := NewSettings(derived.Name, fmtCBFloatMaker(2))
+	setts.configName = "derived_" + series.NamePart(derived.Name)
+	setts.Description = derived.Name + "(" + derived.GetExpr().String() + ")"
+
+	return newGraph(setts, data, usedSeries, sub)
+}
+
+func Derived(stats *app.Stats) []*Graph {
+	collectors := app.InstancesOf[*series.DerivedCollector](stats, app.CollectorDerived)
+	graphs := make([]*Graph, len(collectors))
+	for x, derived := range collectors {
+		graphs[x] = DerivedEntry(derived)
+	}
+	return graphs
+}
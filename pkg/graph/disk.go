@@ -1,13 +1,35 @@
 package graph
 
 import (
-	"maps"
-	"slices"
-
 	"n4/gui-test/pkg/app"
+	"n4/gui-test/pkg/plot"
 	"n4/gui-test/pkg/series"
 )
 
+func init() {
+	RegisterGraphs(app.CollectorDisks, Disks)
+}
+
+// Display settings of disk
+type DiskOptions struct {
+	// Shown instead of mountpoint
+	Alias      string
+	Thresholds []plot.Threshold
+}
+
+// Options of disks by mountpoint
+var diskOptions map[string]DiskOptions
+
+// Sets options of disks by mountpoint. NOTE: Applied to graphs created after,
+// so it's set before graphs are created
+func SetDiskOptions(options map[string]DiskOptions) {
+	diskOptions = options
+}
+
+func diskConfigName(disk *series.DiskStats) string {
+	return "disk_" + series.NamePart(disk.Name) + "_free"
+}
+
 func Disk(disk *series.DiskStats) *Graph {
 	usedSeries := []*series.Entry{
 		&disk.Free,
@@ -19,22 +41,77 @@ func Disk(disk *series.DiskStats) *Graph {
 
 	getMax := func() float64 { return total.GetFirstValue() }
 
-	setts := NewSettings("Free "+disk.Name, fmtCBMem)
+	options := diskOptions[disk.Name]
+	label := disk.Name
+	if options.Alias != "" {
+		label = options.Alias
+	}
+
+	setts := NewSettings("Free "+label, fmtCBMem)
+	setts.GridStepCb = plot.GridStepBytes
+	setts.configName = diskConfigName(disk)
 	setts.AutoMinMaxPadding = 0
+	setts.Description = "Free space of " + disk.Name
+	setts.Thresholds = options.Thresholds
 
-	gr := newGraph(setts, data, usedSeries, sub)
-	gr.updateFunc = func(g *Graph) { g.Limits.Max = getMax() }
+	gr := newGraph(setts, data, usedSeries, sub).withTimeToFull(&disk.TimeToFull)
+	gr.disk = disk
+	gr.updateFunc = func(g *Graph) {
+		g.Limits.Max = getMax()
+		g.NameLabel = "Free " + label
+		if !disk.IsConnected() {
+			g.NameLabel += " (disconnected)"
+		}
+	}
 
 	return gr
 }
 
-// TODO: filter from config
+// Returns disk collector of stats, nil if there is none
+func getDisks(stats *app.Stats) *series.DiskCollector {
+	return app.CollectorOf[*series.DiskCollector](stats, app.CollectorDisks)
+}
+
+// Returns graphs of disks collected, disks are filtered by collector
 func Disks(stats *app.Stats) []*Graph {
-	disks := make([]*Graph, len(stats.Disks.Disks))
-	for x, key := range slices.Sorted(maps.Keys(stats.Disks.Disks)) {
-		disk := stats.Disks.Disks[key]
-		disks[x] = Disk(disk)
+	disks := getDisks(stats).GetDisks()
+	graphs := make([]*Graph, len(disks))
+	for x, disk := range disks {
+		graphs[x] = Disk(disk)
+	}
+
+	return graphs
+}
+
+// Returns graphs of disks found since graphs were created and graphs of
+// disks released by collector. Collection is not changed. NOTE: Graphs are
+// matched by disk, not by name. Disk released and found again between syncs
+// is a new one, so its graph is replaced
+func SyncDisks(stats *app.Stats, graphs Collection) (added, removed []*Graph) {
+	collector := getDisks(stats)
+	if collector == nil {
+		return nil, nil
+	}
+
+	known := make(map[*series.DiskStats]*Graph)
+	for _, g := range graphs {
+		if g.disk != nil {
+			known[g.disk] = g
+		}
 	}
 
-	return disks
+	var newDisks []*Graph
+	for _, disk := range collector.GetDisks() {
+		if _, present := known[disk]; present {
+			delete(known, disk)
+			continue
+		}
+		newDisks = append(newDisks, Disk(disk))
+	}
+	added = attach(stats, app.CollectorDisks, newDisks)
+
+	for _, g := range known {
+		removed = append(removed, g)
+	}
+	return added, removed
 }
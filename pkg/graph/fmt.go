@@ -3,6 +3,8 @@ package graph
 import (
 	"strconv"
 
+	"n4/gui-test/pkg/series"
+
 	"github.com/dustin/go-humanize"
 )
 
@@ -17,3 +19,18 @@ var fmtCBCPU = fmtCBFloatMaker(2)
 func fmtCBMem(value float64) string {
 	return humanize.IBytes(uint64(value))
 }
+
+func fmtCBMemRate(value float64) string {
+	return fmtCBMem(value) + "/s"
+}
+
+// Returns value of top processes metric formatted for the panel
+func FormatTopValue(metric series.TopMetric, value float64) string {
+	switch metric {
+	case series.TopByCPU:
+		return fmtCBCPU(value) + "%"
+	case series.TopByIO:
+		return fmtCBMemRate(value)
+	}
+	return fmtCBMem(value)
+}
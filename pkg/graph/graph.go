@@ -1,16 +1,70 @@
 package graph
 
 import (
+	"math"
+	"slices"
+	"time"
+
+	"n4/gui-test/pkg/app"
+	"n4/gui-test/pkg/plot"
 	"n4/gui-test/pkg/series"
 )
 
+const (
+	// Graphs with time to full warn when their value is going to reach its
+	// limit soon
+	timeToFullWarning  = 30 * time.Minute
+	timeToFullCritical = 5 * time.Minute
+)
+
+// Named data plotted as one of the graph series
+type Dataset struct {
+	Label string
+
+	data *series.EntryData
+}
+
+func (d *Dataset) GetData() *series.EntryData {
+	return d.data
+}
+
+type labeledEntry struct {
+	label string
+	entry *series.Entry
+}
+
+// Subscribes to entries and returns them as datasets in the same order
+func subscribeDatasets(
+	sub *series.Subscriber, entries []labeledEntry,
+) (datasets []*Dataset, usedSeries []*series.Entry) {
+	datasets = make([]*Dataset, len(entries))
+	usedSeries = make([]*series.Entry, len(entries))
+	for x, le := range entries {
+		datasets[x] = &Dataset{Label: le.label, data: le.entry.Subscribe(sub)}
+		usedSeries[x] = le.entry
+	}
+	return datasets, usedSeries
+}
+
 type Graph struct {
 	*Settings
 
-	data       *series.EntryData
+	datasets   []*Dataset
 	series     []*series.Entry
 	subscriber *series.Subscriber
 
+	// Seconds until plotted value reaches its limit, nil if not estimated
+	timeToFull *series.EntryData
+
+	// Statistical overlays, nil if disabled
+	overlays *overlayState
+
+	// Name of collector the graph is registered under and its health
+	collector string
+	status    func() (app.CollectorStatus, bool)
+	// Disk the graph is built from, nil for graphs of other collectors
+	disk *series.DiskStats
+
 	// TODO: I'm not happy with this solution
 	updateFunc func(g *Graph)
 }
@@ -20,28 +74,116 @@ func newGraph(
 	data *series.EntryData,
 	series []*series.Entry,
 	subscriber *series.Subscriber,
+) *Graph {
+	return newMultiGraph(
+		settings,
+		[]*Dataset{{Label: settings.NameLabel, data: data}},
+		series,
+		subscriber,
+	)
+}
+
+func newMultiGraph(
+	settings *Settings,
+	datasets []*Dataset,
+	series []*series.Entry,
+	subscriber *series.Subscriber,
 ) *Graph {
 	return &Graph{
 		Settings:   settings,
-		data:       data,
+		datasets:   datasets,
 		series:     series,
 		subscriber: subscriber,
 	}
 }
 
+// Subscribes graph to time until its value reaches limit, shown in label.
+// Adds thresholds firing when it's close
+func (g *Graph) withTimeToFull(entry *series.Entry) *Graph {
+	g.timeToFull = entry.Subscribe(g.subscriber)
+	g.series = append(g.series, entry)
+	g.Thresholds = slices.Concat(g.Thresholds, []plot.Threshold{
+		{
+			Value:      timeToFullWarning.Seconds(),
+			Level:      plot.ThresholdWarning,
+			Below:      true,
+			TimeToFull: true,
+		},
+		{
+			Value:      timeToFullCritical.Seconds(),
+			Level:      plot.ThresholdCritical,
+			Below:      true,
+			TimeToFull: true,
+		},
+	})
+	return g
+}
+
+// Returns seconds until graph value reaches its limit, NaN if unknown
+func (g *Graph) GetTimeToFull() float64 {
+	if g.timeToFull == nil {
+		return math.NaN()
+	}
+	return g.timeToFull.GetFirstValue()
+}
+
+// Unsubscribes graph from its series, so they are not collected for it
+// anymore. Graph must not be used after it
+func (g *Graph) Release() {
+	for _, entry := range g.series {
+		entry.Unsubscribe(g.subscriber)
+	}
+	g.series = nil
+}
+
 // FIXME: register/unregister series usage
 func (g *Graph) SetActive(active bool) {
 	g.active = active
 }
 
 func (g *Graph) GetData() *series.EntryData {
-	return g.data
+	return g.datasets[0].data
+}
+
+func (g *Graph) GetDatasets() []*Dataset {
+	return g.datasets
+}
+
+// Returns sample interval of the graph series, zero if unknown
+func (g *Graph) GetSampleInterval() time.Duration {
+	if len(g.series) == 0 {
+		return 0
+	}
+	return g.series[0].GetInterval()
+}
+
+// Returns true if any series of the graph got no values lately
+func (g *Graph) IsStale(now time.Time) bool {
+	for _, entry := range g.series {
+		if entry.IsStale(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns error of graph collector, empty if it's ok
+func (g *Graph) GetError() string {
+	if g.status == nil {
+		return ""
+	}
+	status, present := g.status()
+	if !present || status.Health == app.HealthOK {
+		return ""
+	}
+	return status.LastError
 }
 
 func (g *Graph) Update() {
 	if g.updateFunc != nil {
 		g.updateFunc(g)
 	}
+	g.updateOverlays()
 }
 
 type Collection []*Graph
@@ -55,3 +197,50 @@ func (gl Collection) ActiveNum() int {
 	}
 	return count
 }
+
+// Returns collection with graphs added after the last graph of the same
+// collector, or at the end
+func (gl Collection) Add(graphs ...*Graph) Collection {
+	ret := slices.Clone(gl)
+	for _, g := range graphs {
+		idx := len(ret)
+		for x, known := range ret {
+			if known.collector == g.collector {
+				idx = x + 1
+			}
+		}
+		ret = slices.Insert(ret, idx, g)
+	}
+	return ret
+}
+
+// Returns collection without graphs. NOTE: Removed graphs are not released,
+// they may still be drawn until the new collection is swapped in
+func (gl Collection) Remove(graphs ...*Graph) Collection {
+	return slices.DeleteFunc(slices.Clone(gl), func(g *Graph) bool {
+		return slices.Contains(graphs, g)
+	})
+}
+
+// New collection of graphs built off the UI goroutine. It's applied on the
+// goroutine drawing graphs, so graphs and config are not changed while
+// they're used
+type Update struct {
+	Graphs Collection
+	// Runs before the swap, i.e. applies config to added graphs. May be nil
+	Prepare func()
+	// Graphs released after the swap
+	Removed []*Graph
+}
+
+// Prepares and returns the new collection, removed graphs are released.
+// Must be called on the goroutine drawing graphs
+func (u Update) Apply() Collection {
+	if u.Prepare != nil {
+		u.Prepare()
+	}
+	for _, g := range u.Removed {
+		g.Release()
+	}
+	return u.Graphs
+}
@@ -0,0 +1,152 @@
+package graph
+
+import (
+	"math"
+	"time"
+
+	"n4/gui-test/pkg/plot"
+	"n4/gui-test/pkg/series"
+	"n4/gui-test/pkg/tickstore"
+)
+
+const (
+	// Window of statistical overlays unless it's set
+	DefaultOverlayWindow = time.Minute
+	// Quantile of the p95 overlay
+	overlayQuantile = 0.95
+)
+
+// Statistics of plotted values drawn over the plot. Stacked graphs use the
+// sum of their datasets, other graphs the first dataset
+type Overlays struct {
+	Mean   bool
+	P95    bool
+	MinMax bool
+	// Rolling window the statistics are calculated over
+	Window time.Duration
+}
+
+func (o Overlays) IsEmpty() bool {
+	return !o.Mean && !o.P95 && !o.MinMax
+}
+
+// NOTE: Statistics are pushed once per new sample and their data is shifted
+// together with graph data, so cost doesn't grow with the time range
+type overlayState struct {
+	Overlays
+
+	stats *tickstore.RollingStats
+	// Aligned with graph data, the latest value is the first one
+	mean, p95, low, high *series.EntryData
+
+	// Samples of the first series already pushed
+	samples uint64
+}
+
+// Sets statistical overlays, empty overlays disable them
+func (g *Graph) SetOverlays(overlays Overlays) {
+	if overlays.IsEmpty() {
+		g.overlays = nil
+		return
+	}
+	if overlays.Window <= 0 {
+		overlays.Window = DefaultOverlayWindow
+	}
+	g.overlays = &overlayState{Overlays: overlays}
+}
+
+func (g *Graph) GetOverlays() Overlays {
+	if g.overlays == nil {
+		return Overlays{}
+	}
+	return g.overlays.Overlays
+}
+
+// Returns plotted value at idx, the latest value is the first one
+func (g *Graph) getPlottedValue(idx int) float64 {
+	if g.Mode != plot.ModeStacked {
+		return g.datasets[0].data.GetValue(idx)
+	}
+	sum := math.NaN()
+	for _, dataset := range g.datasets {
+		val := dataset.data.GetValue(idx)
+		if math.IsNaN(val) {
+			continue
+		}
+		if math.IsNaN(sum) {
+			sum = 0
+		}
+		sum += val
+	}
+	return sum
+}
+
+// Pushes samples added since the previous update to overlay statistics
+func (g *Graph) updateOverlays() {
+	o := g.overlays
+	if o == nil || len(g.series) == 0 {
+		return
+	}
+
+	interval := g.GetSampleInterval()
+	if interval <= 0 {
+		interval = time.Second
+	}
+	windowSize := max(int(o.Window/interval), 1)
+	size := g.GetData().GetSize()
+	samples := g.series[0].GetSamples()
+
+	// NOTE: Window is set up again when interval gets known and after graph
+	// missed more samples than it shows, i.e. while it was hidden
+	if o.stats == nil || o.stats.GetSize() != windowSize || samples-o.samples > uint64(size) {
+		o.stats = tickstore.NewRollingStats(windowSize)
+		o.mean, o.p95 = newGapData(size), newGapData(size)
+		o.low, o.high = newGapData(size), newGapData(size)
+		o.samples = samples - min(samples, uint64(size))
+	}
+
+	newSamples := int(samples - o.samples)
+	if newSamples == 0 {
+		return
+	}
+	mean, p95 := make([]float64, newSamples), make([]float64, newSamples)
+	low, high := make([]float64, newSamples), make([]float64, newSamples)
+	for idx := newSamples - 1; idx >= 0; idx-- {
+		o.stats.Push(g.getPlottedValue(idx))
+		mean[idx], p95[idx] = o.stats.Mean(), o.stats.Quantile(overlayQuantile)
+		low[idx], high[idx] = o.stats.Min(), o.stats.Max()
+	}
+	o.mean.AddValues(mean...)
+	o.p95.AddValues(p95...)
+	o.low.AddValues(low...)
+	o.high.AddValues(high...)
+	o.samples = samples
+}
+
+// Returns overlays enabled for the plot widget
+func (g *Graph) getPlotOverlays() plot.Overlays {
+	o := g.overlays
+	if o == nil || o.stats == nil {
+		return plot.Overlays{}
+	}
+	var overlays plot.Overlays
+	if o.Mean {
+		overlays.Mean = o.mean.GetValues()
+	}
+	if o.P95 {
+		overlays.P95 = o.p95.GetValues()
+	}
+	if o.MinMax {
+		overlays.Min, overlays.Max = o.low.GetValues(), o.high.GetValues()
+	}
+	return overlays
+}
+
+func newGapData(size int) *series.EntryData {
+	data := series.NewEntryData(size)
+	values := data.GetValues()
+	for x := range values {
+		values[x] = math.NaN()
+	}
+	return data
+}
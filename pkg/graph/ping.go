@@ -0,0 +1,69 @@
+package graph
+
+import (
+	"n4/gui-test/pkg/app"
+	"n4/gui-test/pkg/plot"
+	"n4/gui-test/pkg/series"
+)
+
+func init() {
+	RegisterGraphs(app.CollectorPings, Pings)
+}
+
+func fmtCBLatency(value float64) string {
+	return fmtCBFloatMaker(1)(value) + "ms"
+}
+
+func newPingRTT(ping *series.PingCollector, configName string) *Graph {
+	sub := &series.Subscriber{}
+	datasets, usedSeries := subscribeDatasets(sub, []labeledEntry{
+		{"RTT", &ping.RTT},
+		{"Jitter", &ping.Jitter},
+	})
+
+	setts := NewSettings(ping.Name+" RTT", fmtCBLatency)
+	setts.configName = configName
+	setts.Limits = Limits{0, 10}
+	setts.Description = ping.Name + " round trip and jitter(ms, " +
+		ping.Probe.String() + " " + ping.Address + ")"
+
+	return newMultiGraph(setts, datasets, usedSeries, sub)
+}
+
+func newPingLoss(ping *series.PingCollector, configName string) *Graph {
+	entry := &ping.Loss
+	usedSeries := []*series.Entry{entry}
+	sub := &series.Subscriber{}
+	data := entry.Subscribe(sub)
+
+	setts := NewSettings(ping.Name+" Loss%", fmtCBFloatMaker(0))
+	setts.configName = configName
+	setts.Limits = Limits{0, 100}
+	setts.GridStepCb = plot.GridStepPercent
+	setts.AutoMinMaxPadding = 0
+	setts.Thresholds = []plot.Threshold{
+		{Value: 5, Level: plot.ThresholdWarning},
+		{Value: 20, Level: plot.ThresholdCritical},
+	}
+	setts.Description = ping.Name + " lost probes(percent of the last ones)"
+
+	return newGraph(setts, data, usedSeries, sub)
+}
+
+// Returns latency and packet loss graphs of a ping target
+func Ping(ping *series.PingCollector) []*Graph {
+	prefix := "ping_" + series.NamePart(ping.Name) + "_"
+	return []*Graph{
+		newPingRTT(ping, prefix+"rtt"),
+		newPingLoss(ping, prefix+"loss"),
+	}
+}
+
+func Pings(stats *app.Stats) []*Graph {
+	var graphs []*Graph
+	pings := app.InstancesOf[*series.PingCollector](stats, app.CollectorPings)
+	for _, ping := range pings {
+		graphs = append(graphs, Ping(ping)...)
+	}
+	return graphs
+}
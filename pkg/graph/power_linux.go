@@ -0,0 +1,118 @@
+package graph
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"n4/gui-test/pkg/app"
+	"n4/gui-test/pkg/plot"
+	"n4/gui-test/pkg/series"
+)
+
+// Default low battery levels, percent of full charge
+const (
+	batteryLowWarning  = 20
+	batteryLowCritical = 10
+)
+
+func init() {
+	RegisterGraphs(app.CollectorPower, Power)
+}
+
+// Returns duration in seconds as hours and minutes
+func fmtCBDuration(value float64) string {
+	minutes := int(value / 60)
+	return fmt.Sprintf("%dh%02dm", minutes/60, minutes%60)
+}
+
+func newBatteryCapacity(battery *series.BatteryStats, configName string) *Graph {
+	entry := &battery.Capacity
+	usedSeries := []*series.Entry{entry}
+	sub := &series.Subscriber{}
+	data := entry.Subscribe(sub)
+
+	setts := NewSettings(battery.Name+" Charge%", fmtCBFloatMaker(0))
+	setts.configName = configName
+	setts.Limits = Limits{0, 100}
+	setts.GridStepCb = plot.GridStepPercent
+	setts.AutoMinMaxPadding = 0
+	setts.Thresholds = []plot.Threshold{
+		{Value: batteryLowWarning, Level: plot.ThresholdWarning, Below: true},
+		{Value: batteryLowCritical, Level: plot.ThresholdCritical, Below: true},
+	}
+	setts.Description = battery.Name + " charge(percent of full)"
+
+	return newGraph(setts, data, usedSeries, sub)
+}
+
+func newBatteryRate(battery *series.BatteryStats, configName string) *Graph {
+	entry := &battery.Rate
+	usedSeries := []*series.Entry{entry}
+	sub := &series.Subscriber{}
+	data := entry.Subscribe(sub)
+
+	setts := NewSettings(battery.Name+" Rate", fmtCBFloatMaker(1))
+	setts.configName = configName
+	setts.Limits = Limits{-10, 10}
+	setts.Description = battery.Name + " charge rate(W, negative while discharging)"
+
+	return newGraph(setts, data, usedSeries, sub)
+}
+
+func newBatteryTime(battery *series.BatteryStats, configName string) *Graph {
+	entry := &battery.TimeRemaining
+	usedSeries := []*series.Entry{entry}
+	sub := &series.Subscriber{}
+	data := entry.Subscribe(sub)
+
+	setts := NewSettings(battery.Name+" Time", fmtCBDuration)
+	setts.configName = configName
+	setts.Limits = Limits{0, 3600}
+	setts.AutoMinMaxPadding = 0
+	setts.Description = battery.Name + " time until empty or until full while charging"
+
+	return newGraph(setts, data, usedSeries, sub)
+}
+
+// Returns charge, rate and remaining time graphs of a battery
+func Battery(battery *series.BatteryStats) []*Graph {
+	prefix := "battery_" + series.NamePart(battery.Name) + "_"
+	return []*Graph{
+		newBatteryCapacity(battery, prefix+"capacity"),
+		newBatteryRate(battery, prefix+"rate"),
+		newBatteryTime(battery, prefix+"time"),
+	}
+}
+
+func PowerAC(stats *app.Stats) *Graph {
+	power := app.CollectorOf[*series.PowerSupplyCollector](stats, app.CollectorPower)
+	entry := &power.AC
+	usedSeries := []*series.Entry{entry}
+	sub := &series.Subscriber{}
+	data := entry.Subscribe(sub)
+
+	setts := NewSettings("AC", fmtCBFloatMaker(0))
+	setts.configName = "power_ac"
+	setts.AutoMinMaxPadding = 0
+	setts.Description = "On mains power(1) or battery(0)"
+
+	return newGraph(setts, data, usedSeries, sub)
+}
+
+// Returns graphs of batteries and AC state, none on machines without
+// batteries
+func Power(stats *app.Stats) []*Graph {
+	power := app.CollectorOf[*series.PowerSupplyCollector](stats, app.CollectorPower)
+	if len(power.Batteries) == 0 {
+		return nil
+	}
+	var graphs []*Graph
+	if power.HasAC() {
+		graphs = append(graphs, PowerAC(stats))
+	}
+	for _, name := range slices.Sorted(maps.Keys(power.Batteries)) {
+		graphs = append(graphs, Battery(power.Batteries[name])...)
+	}
+	return graphs
+}
@@ -0,0 +1,140 @@
+package graph
+
+import (
+	"n4/gui-test/pkg/app"
+	"n4/gui-test/pkg/plot"
+	"n4/gui-test/pkg/series"
+
+	"github.com/dustin/go-humanize"
+)
+
+func init() {
+	RegisterGraphs(app.CollectorProcesses, Processes)
+	RegisterGraphs(app.CollectorProcessTrees, ProcessTrees)
+}
+
+// Returns CPU and memory graphs of a monitored process
+func Process(proc *series.ProcessCollector) []*Graph {
+	prefix := "proc_" + series.NamePart(proc.Name) + "_"
+	return []*Graph{
+		newProcCPUPerc(
+			&proc.CPU.Perc, proc.Name+" CPU %", prefix+"cpu_perc",
+			proc.Name+" CPU usage percent",
+		),
+		newProcCPUTimes(
+			proc.CPU, proc.Name+" CPU", prefix+"cpu_times",
+			proc.Name+" CPU usage by time type(percent of one core)",
+		),
+		newProcMem(
+			&proc.Mem.RSS, proc.Name+" RSS", prefix+"mem_rss",
+			proc.Name+" resident memory",
+		),
+		newProcMem(
+			&proc.Mem.VMS, proc.Name+" VMS", prefix+"mem_vms",
+			proc.Name+" virtual memory",
+		),
+		newProcMem(
+			&proc.Mem.Swap, proc.Name+" Swap", prefix+"mem_swap",
+			proc.Name+" swapped memory",
+		),
+	}
+}
+
+// Returns graphs of process added to stats after graphs were created, i.e.
+// focused one. They show errors of processes collector like registry graphs
+func AddedProcess(stats *app.Stats, proc *series.ProcessCollector) []*Graph {
+	return attach(stats, app.CollectorProcesses, Process(proc))
+}
+
+func Processes(stats *app.Stats) []*Graph {
+	var graphs []*Graph
+	procs := app.InstancesOf[*series.ProcessCollector](stats, app.CollectorProcesses)
+	for _, proc := range procs {
+		graphs = append(graphs, Process(proc)...)
+	}
+	return graphs
+}
+
+func newProcTreeChildren(tree *series.ProcessTreeCollector, configName string) *Graph {
+	entry := &tree.Children
+	usedSeries := []*series.Entry{entry}
+	sub := &series.Subscriber{}
+	data := entry.Subscribe(sub)
+
+	setts := NewSettings(tree.Name+" Children", fmtCBFloatMaker(0))
+	setts.configName = configName
+	setts.Limits = Limits{0, 10}
+	setts.Description = tree.Name + " number of descendant processes"
+
+	gr := newGraph(setts, data, usedSeries, sub)
+
+	return gr
+}
+
+// Returns stacked RSS of the largest children, labels follow the children
+// currently taking the places
+func newProcTreeTop(tree *series.ProcessTreeCollector, configName string) *Graph {
+	entries := make([]labeledEntry, len(tree.Top))
+	for x, top := range tree.Top {
+		entries[x] = labeledEntry{"-", &top.RSS}
+	}
+	sub := &series.Subscriber{}
+	datasets, usedSeries := subscribeDatasets(sub, entries)
+
+	setts := NewSettings(tree.Name+" Top RSS", fmtCBMem)
+	setts.GridStepCb = plot.GridStepBytes
+	setts.configName = configName
+	setts.Limits = Limits{0, humanize.MiByte}
+	setts.Mode = plot.ModeStacked
+	setts.Description = tree.Name + " resident memory of the largest children"
+
+	gr := newMultiGraph(setts, datasets, usedSeries, sub)
+	gr.updateFunc = func(g *Graph) {
+		for x, top := range tree.Top {
+			name := top.GetName()
+			if name == "" {
+				name = "-"
+			}
+			g.datasets[x].Label = name
+		}
+	}
+
+	return gr
+}
+
+// Returns graphs of memory and CPU usage summed over a process tree
+func ProcessTree(tree *series.ProcessTreeCollector) []*Graph {
+	prefix := "proc_tree_" + series.NamePart(tree.Name) + "_"
+	graphs := []*Graph{
+		newProcCPUPerc(
+			&tree.CPUPerc, tree.Name+" CPU %", prefix+"cpu_perc",
+			tree.Name+" CPU usage percent of all processes",
+		),
+		newProcMem(
+			&tree.Mem.RSS, tree.Name+" RSS", prefix+"mem_rss",
+			tree.Name+" resident memory of all processes",
+		),
+		newProcMem(
+			&tree.Mem.VMS, tree.Name+" VMS", prefix+"mem_vms",
+			tree.Name+" virtual memory of all processes",
+		),
+		newProcMem(
+			&tree.Mem.Swap, tree.Name+" Swap", prefix+"mem_swap",
+			tree.Name+" swapped memory of all processes",
+		),
+		newProcTreeChildren(tree, prefix+"children"),
+	}
+	if len(tree.Top) > 0 {
+		graphs = append(graphs, newProcTreeTop(tree, prefix+"top_rss"))
+	}
+	return graphs
+}
+
+func ProcessTrees(stats *app.Stats) []*Graph {
+	var graphs []*Graph
+	trees := app.InstancesOf[*series.ProcessTreeCollector](stats, app.CollectorProcessTrees)
+	for _, tree := range trees {
+		graphs = append(graphs, ProcessTree(tree)...)
+	}
+	return graphs
+}
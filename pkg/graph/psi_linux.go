@@ -0,0 +1,89 @@
+package graph
+
+import (
+	"n4/gui-test/pkg/app"
+	"n4/gui-test/pkg/plot"
+	"n4/gui-test/pkg/series"
+)
+
+func init() {
+	RegisterGraphs(app.CollectorPSI, func(stats *app.Stats) []*Graph {
+		return []*Graph{
+			PSICPU(stats),
+			PSIMemory(stats),
+			PSIIO(stats),
+			PSIStall(stats),
+		}
+	})
+}
+
+// Returns graph of some and full stall percent averaged over 10 seconds
+func newPSIAvg(
+	resource *series.PSIResourceCollector,
+	label, configName, description string,
+	warning, critical float64,
+) *Graph {
+	sub := &series.Subscriber{}
+	datasets, usedSeries := subscribeDatasets(sub, []labeledEntry{
+		{"Some", &resource.SomeAvg10},
+		{"Full", &resource.FullAvg10},
+	})
+
+	setts := NewSettings(label, fmtCBFloatMaker(2))
+	setts.configName = configName
+	setts.Limits = Limits{0, 100}
+	setts.GridStepCb = plot.GridStepPercent
+	setts.AutoMinMaxPadding = 0
+	setts.Thresholds = []plot.Threshold{
+		{Value: warning, Level: plot.ThresholdWarning},
+		{Value: critical, Level: plot.ThresholdCritical},
+	}
+	setts.Description = description
+
+	return newMultiGraph(setts, datasets, usedSeries, sub)
+}
+
+func PSICPU(stats *app.Stats) *Graph {
+	psi := app.CollectorOf[*series.PSICollector](stats, app.CollectorPSI)
+	return newPSIAvg(
+		psi.CPU, "CPU PSI%", "psi_cpu",
+		"Time tasks stalled on CPU(percent, 10s avg)", 20, 50,
+	)
+}
+
+func PSIMemory(stats *app.Stats) *Graph {
+	psi := app.CollectorOf[*series.PSICollector](stats, app.CollectorPSI)
+	// NOTE: Memory stalls hurt more than CPU ones, swapping or reclaim
+	// makes everything slow
+	return newPSIAvg(
+		psi.Memory, "Mem PSI%", "psi_memory",
+		"Time tasks stalled on memory(percent, 10s avg)", 10, 25,
+	)
+}
+
+func PSIIO(stats *app.Stats) *Graph {
+	psi := app.CollectorOf[*series.PSICollector](stats, app.CollectorPSI)
+	return newPSIAvg(
+		psi.IO, "IO PSI%", "psi_io",
+		"Time tasks stalled on IO(percent, 10s avg)", 20, 50,
+	)
+}
+
+func PSIStall(stats *app.Stats) *Graph {
+	psi := app.CollectorOf[*series.PSICollector](stats, app.CollectorPSI)
+	sub := &series.Subscriber{}
+	datasets, usedSeries := subscribeDatasets(sub, []labeledEntry{
+		{"CPU", &psi.CPU.SomeRate},
+		{"Mem", &psi.Memory.SomeRate},
+		{"IO", &psi.IO.SomeRate},
+	})
+
+	setts := NewSettings("Stall%", fmtCBFloatMaker(2))
+	setts.configName = "psi_stall"
+	setts.Limits = Limits{0, 100}
+	setts.GridStepCb = plot.GridStepPercent
+	setts.AutoMinMaxPadding = 0
+	setts.Description = "Time some tasks stalled per tick(percent)"
+
+	return newMultiGraph(setts, datasets, usedSeries, sub)
+}
@@ -0,0 +1,48 @@
+package graph
+
+import (
+	"n4/gui-test/pkg/app"
+)
+
+type graphsRegistration struct {
+	collector string
+	newGraphs func(stats *app.Stats) []*Graph
+}
+
+var registry []graphsRegistration
+
+// Registers builder of graphs of collector registered in app under the same
+// name. Collector may have several builders, i.e. from files with build tags
+func RegisterGraphs(collector string, newGraphs func(stats *app.Stats) []*Graph) {
+	registry = append(registry, graphsRegistration{collector, newGraphs})
+}
+
+// Returns graphs of collectors created by stats, ordered as collectors in
+// app registry. Graphs show errors of their collector
+func All(stats *app.Stats) []*Graph {
+	var graphs []*Graph
+	for _, info := range app.Collectors() {
+		if !stats.HasCollector(info.Name) {
+			continue
+		}
+		for _, reg := range registry {
+			if reg.collector == info.Name {
+				graphs = append(graphs, attach(stats, info.Name, reg.newGraphs(stats))...)
+			}
+		}
+	}
+	return graphs
+}
+
+// Returns graphs bound to collector, they show its errors and are grouped
+// with its graphs when added to collection
+func attach(stats *app.Stats, collector string, graphs []*Graph) []*Graph {
+	status := func() (app.CollectorStatus, bool) {
+		return stats.CollectorStatus(collector)
+	}
+	for _, gr := range graphs {
+		gr.collector = collector
+		gr.status = status
+	}
+	return graphs
+}
@@ -2,25 +2,61 @@ package graph
 
 import (
 	"n4/gui-test/pkg/app"
+	"n4/gui-test/pkg/plot"
 	"n4/gui-test/pkg/series"
 )
 
+func init() {
+	RegisterGraphs(app.CollectorSelf, func(stats *app.Stats) []*Graph {
+		return []*Graph{
+			SelfUpdate(stats),
+			SelfErrors(stats),
+			SelfFramerate(stats),
+			SelfRuntimeMemAlloc(stats),
+			SelfRuntimeMemSys(stats),
+		}
+	})
+}
+
+// Returns time spent to update stats, stacked per collector
 func SelfUpdate(stats *app.Stats) *Graph {
-	entry := &stats.StatsUpdate
-	usedSeries := []*series.Entry{entry}
+	var entries []labeledEntry
+	for _, update := range stats.CollectorUpdates() {
+		entries = append(entries, labeledEntry{update.Name, update.Entry})
+	}
 	sub := &series.Subscriber{}
-	data := entry.Subscribe(sub)
+	datasets, usedSeries := subscribeDatasets(sub, entries)
 
 	setts := NewSettings("Update", fmtCBFloatMaker(4))
 	setts.configName = "self_update"
 	setts.Limits = Limits{0, 0.05}
-	setts.Description = "Time in seconds spent to update stats"
+	setts.Mode = plot.ModeStacked
+	setts.Description = "Time in seconds spent to update stats per collector"
 
-	gr := newGraph(setts, data, usedSeries, sub)
+	gr := newMultiGraph(setts, datasets, usedSeries, sub)
 
 	return gr
 }
 
+// Returns error counts of collectors
+func SelfErrors(stats *app.Stats) *Graph {
+	var entries []labeledEntry
+	for _, status := range stats.CollectorStatuses() {
+		entries = append(entries, labeledEntry{status.Name, status.ErrorsEntry})
+	}
+	sub := &series.Subscriber{}
+	datasets, usedSeries := subscribeDatasets(sub, entries)
+
+	setts := NewSettings("Errors", fmtCBFloatMaker(0))
+	setts.configName = "self_errors"
+	setts.active = false
+	setts.Limits = Limits{0, 10}
+	setts.AutoMinMaxPadding = 0
+	setts.Description = "Collection errors since start per collector"
+
+	return newMultiGraph(setts, datasets, usedSeries, sub)
+}
+
 func SelfFramerate(stats *app.Stats) *Graph {
 	entry := &stats.SelfFramerate
 	usedSeries := []*series.Entry{entry}
@@ -2,76 +2,72 @@ package graph
 
 import (
 	"n4/gui-test/pkg/app"
+	"n4/gui-test/pkg/plot"
 	"n4/gui-test/pkg/series"
 )
 
-func selfCPU(name string, label string, entry *series.Entry) *Graph {
-	usedSeries := []*series.Entry{entry}
+func init() {
+	RegisterGraphs(app.CollectorSelfCPU, func(stats *app.Stats) []*Graph {
+		return []*Graph{
+			SelfCPUTimes(stats),
+			SelfCPUPerc(stats),
+		}
+	})
+}
+
+func newProcCPUTimes(
+	cpu *series.ProcessCPUCollector, label, configName, description string,
+) *Graph {
 	sub := &series.Subscriber{}
-	data := entry.Subscribe(sub)
+	datasets, usedSeries := subscribeDatasets(sub, []labeledEntry{
+		{"User", &cpu.User},
+		{"Sys", &cpu.Sys},
+		{"Iowait", &cpu.Iowait},
+		{"Irq", &cpu.Irq},
+		{"Softirq", &cpu.Softirq},
+		{"Steal", &cpu.Steal},
+		{"Nice", &cpu.Nice},
+	})
 
 	setts := NewSettings(label, fmtCBCPU)
-	setts.configName = "self_cpu_" + name
-	setts.Description = "Overlay process CPU usage"
+	setts.configName = configName
+	setts.Mode = plot.ModeStacked
+	setts.Description = description
 
-	gr := newGraph(setts, data, usedSeries, sub)
+	gr := newMultiGraph(setts, datasets, usedSeries, sub)
 
 	return gr
 }
 
-func SelfCPUSys(stats *app.Stats) *Graph {
-	return selfCPU("sys", "CPU Sys", &stats.SelfCPU.Sys)
-}
-
-func SelfCPUUser(stats *app.Stats) *Graph {
-	return selfCPU("user", "CPU User", &stats.SelfCPU.User)
-}
-
-func SelfCPUIdle(stats *app.Stats) *Graph {
-	return selfCPU("idle", "CPU Idle", &stats.SelfCPU.Idle)
-}
-
-func SelfCPUIowait(stats *app.Stats) *Graph {
-	return selfCPU("iowait", "CPU Iowait", &stats.SelfCPU.Iowait)
-}
-
-func SelfCPUSteal(stats *app.Stats) *Graph {
-	return selfCPU("steal", "CPU Steal", &stats.SelfCPU.Steal)
-}
-
-func SelfCPUIrq(stats *app.Stats) *Graph {
-	return selfCPU("irq", "CPU Irq", &stats.SelfCPU.Irq)
-}
-
-func SelfCPUGuest(stats *app.Stats) *Graph {
-	return selfCPU("guest", "CPU Guest", &stats.SelfCPU.Guest)
-}
-
-func SelfCPUSoftirq(stats *app.Stats) *Graph {
-	return selfCPU("softirq", "CPU Softirq", &stats.SelfCPU.Softirq)
-}
-
-func SelfCPUNice(stats *app.Stats) *Graph {
-	return selfCPU("nice", "CPU Nice", &stats.SelfCPU.Nice)
-}
-
-func SelfCPUGuestNice(stats *app.Stats) *Graph {
-	return selfCPU("guestnice", "CPU GuestNice", &stats.SelfCPU.GuestNice)
-}
-
-func SelfCPUPerc(stats *app.Stats) *Graph {
-	entry := &stats.SelfCPU.Perc
+func newProcCPUPerc(entry *series.Entry, label, configName, description string) *Graph {
 	usedSeries := []*series.Entry{entry}
 	sub := &series.Subscriber{}
 	data := entry.Subscribe(sub)
 
-	setts := NewSettings("CPU %", fmtCBCPU)
-	setts.configName = "self_cpu_perc"
+	setts := NewSettings(label, fmtCBCPU)
+	setts.configName = configName
 	setts.Limits = Limits{0, 100}
+	setts.GridStepCb = plot.GridStepPercent
 	setts.AutoMinMaxPadding = 0
-	setts.Description = "Overlay process CPU usage percent"
+	setts.Description = description
 
 	gr := newGraph(setts, data, usedSeries, sub)
 
 	return gr
 }
+
+func SelfCPUTimes(stats *app.Stats) *Graph {
+	selfCPU := app.CollectorOf[*series.ProcessCPUCollector](stats, app.CollectorSelfCPU)
+	return newProcCPUTimes(
+		selfCPU, "CPU", "self_cpu_times",
+		"Overlay process CPU usage by time type(percent of one core)",
+	)
+}
+
+func SelfCPUPerc(stats *app.Stats) *Graph {
+	selfCPU := app.CollectorOf[*series.ProcessCPUCollector](stats, app.CollectorSelfCPU)
+	return newProcCPUPerc(
+		&selfCPU.Perc, "CPU %", "self_cpu_perc",
+		"Overlay process CPU usage percent",
+	)
+}
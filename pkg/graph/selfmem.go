@@ -2,52 +2,77 @@ package graph
 
 import (
 	"n4/gui-test/pkg/app"
+	"n4/gui-test/pkg/plot"
 	"n4/gui-test/pkg/series"
 
 	"github.com/dustin/go-humanize"
 )
 
-func newSelfMem(name string, label string, entry *series.Entry) *Graph {
+func init() {
+	RegisterGraphs(app.CollectorSelfMem, func(stats *app.Stats) []*Graph {
+		return []*Graph{
+			SelfMemRSS(stats),
+			SelfMemVMS(stats),
+			SelfMemHWM(stats),
+			SelfMemData(stats),
+			SelfMemStack(stats),
+			SelfMemLocked(stats),
+			SelfMemSwap(stats),
+		}
+	})
+}
+
+func newProcMem(entry *series.Entry, label, configName, description string) *Graph {
 	usedSeries := []*series.Entry{entry}
 	sub := &series.Subscriber{}
 	data := entry.Subscribe(sub)
 
 	setts := NewSettings(label, fmtCBMem)
-	setts.configName = "self_mem_" + name
+	setts.GridStepCb = plot.GridStepBytes
+	setts.configName = configName
 	setts.Limits = Limits{0, humanize.MiByte}
-	setts.Description = "Overlay process memory usage"
+	setts.Description = description
 
 	gr := newGraph(setts, data, usedSeries, sub)
 
 	return gr
 }
 
+func newSelfMem(name string, label string, entry *series.Entry) *Graph {
+	return newProcMem(entry, label, "self_mem_"+name, "Overlay process memory usage")
+}
+
+// Returns memory collector of the overlay process
+func getSelfMem(stats *app.Stats) *series.ProcessMemCollector {
+	return app.CollectorOf[*series.ProcessMemCollector](stats, app.CollectorSelfMem)
+}
+
 func SelfMemRSS(stats *app.Stats) *Graph {
-	return newSelfMem("rss", "RSS", &stats.SelfMem.RSS)
+	return newSelfMem("rss", "RSS", &getSelfMem(stats).RSS)
 }
 
 func SelfMemVMS(stats *app.Stats) *Graph {
-	return newSelfMem("vms", "VMS", &stats.SelfMem.VMS)
+	return newSelfMem("vms", "VMS", &getSelfMem(stats).VMS)
 }
 
 func SelfMemHWM(stats *app.Stats) *Graph {
-	return newSelfMem("hwm", "HWM", &stats.SelfMem.HWM)
+	return newSelfMem("hwm", "HWM", &getSelfMem(stats).HWM)
 }
 
 func SelfMemData(stats *app.Stats) *Graph {
-	return newSelfMem("data", "Data", &stats.SelfMem.Data)
+	return newSelfMem("data", "Data", &getSelfMem(stats).Data)
 }
 
 func SelfMemStack(stats *app.Stats) *Graph {
-	return newSelfMem("stack", "Stack", &stats.SelfMem.Stack)
+	return newSelfMem("stack", "Stack", &getSelfMem(stats).Stack)
 }
 
 func SelfMemLocked(stats *app.Stats) *Graph {
-	return newSelfMem("locked", "Locked", &stats.SelfMem.Locked)
+	return newSelfMem("locked", "Locked", &getSelfMem(stats).Locked)
 }
 
 func SelfMemSwap(stats *app.Stats) *Graph {
-	return newSelfMem("swap", "Swap", &stats.SelfMem.Swap)
+	return newSelfMem("swap", "Swap", &getSelfMem(stats).Swap)
 }
 
 func SelfRuntimeMemAlloc(stats *app.Stats) *Graph {
@@ -0,0 +1,66 @@
+package graph
+
+import (
+	"maps"
+	"math"
+	"slices"
+
+	"n4/gui-test/pkg/app"
+	"n4/gui-test/pkg/plot"
+	"n4/gui-test/pkg/series"
+)
+
+func init() {
+	RegisterGraphs(app.CollectorSensors, Sensors)
+}
+
+// Returns value formatting and description suffix of sensor kind
+func sensorFormat(kind series.SensorKind) (plot.FormatCallback, string) {
+	switch kind {
+	case series.SensorFan:
+		return fmtCBFloatMaker(0), " fan speed(RPM)"
+	case series.SensorVoltage:
+		return fmtCBFloatMaker(3), " voltage(V)"
+	case series.SensorPower:
+		return fmtCBFloatMaker(1), " power(W)"
+	}
+	return fmtCBFloatMaker(1), " temperature(°C)"
+}
+
+// Returns graph of sensor with limits reported by the chip as thresholds
+func Sensor(sensor *series.SensorStats) *Graph {
+	usedSeries := []*series.Entry{&sensor.Value}
+	sub := &series.Subscriber{}
+	data := sensor.Value.Subscribe(sub)
+
+	fmtCb, description := sensorFormat(sensor.Kind)
+	setts := NewSettings(sensor.Name, fmtCb)
+	setts.configName = "sensor_" + series.NamePart(sensor.Name)
+	setts.Description = sensor.Name + description
+	// NOTE: Machines have dozens of sensors, needed ones are enabled by user
+	setts.active = false
+	if sensor.Kind == series.SensorTemp {
+		setts.Limits = Limits{0, 100}
+	}
+	for _, limit := range []plot.Threshold{
+		{Value: sensor.Min, Level: plot.ThresholdWarning, Below: true},
+		{Value: sensor.Max, Level: plot.ThresholdWarning},
+		{Value: sensor.Crit, Level: plot.ThresholdCritical},
+	} {
+		if !math.IsNaN(limit.Value) {
+			setts.Thresholds = append(setts.Thresholds, limit)
+		}
+	}
+
+	return newGraph(setts, data, usedSeries, sub)
+}
+
+func Sensors(stats *app.Stats) []*Graph {
+	collector := app.CollectorOf[*series.SensorsCollector](stats, app.CollectorSensors)
+	sensors := collector.Sensors
+	graphs := make([]*Graph, len(sensors))
+	for x, key := range slices.Sorted(maps.Keys(sensors)) {
+		graphs[x] = Sensor(sensors[key])
+	}
+	return graphs
+}
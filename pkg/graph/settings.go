@@ -19,11 +19,17 @@ type Settings struct {
 	Description string
 
 	ValueLabelFormatCb plot.FormatCallback
+	GridStepCb         plot.StepCallback
 
 	Limits            Limits
 	AutoMinMaxPadding float64
 
+	Thresholds []plot.Threshold
+
 	Flags []plot.Flag
+
+	// How multiple datasets share the plot
+	Mode plot.Mode
 }
 
 func NewSettings(nameLabel string, formatCb plot.FormatCallback) *Settings {
@@ -33,6 +39,7 @@ func NewSettings(nameLabel string, formatCb plot.FormatCallback) *Settings {
 		NameLabel: nameLabel,
 
 		ValueLabelFormatCb: formatCb,
+		GridStepCb:         plot.GridStepDecimal,
 
 		Limits: Limits{0, 1},
 
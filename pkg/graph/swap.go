@@ -0,0 +1,35 @@
+package graph
+
+import (
+	"n4/gui-test/pkg/app"
+	"n4/gui-test/pkg/plot"
+	"n4/gui-test/pkg/series"
+)
+
+func init() {
+	RegisterGraphs(app.CollectorSwap, func(stats *app.Stats) []*Graph {
+		return []*Graph{SysSwapUsed(stats)}
+	})
+}
+
+func SysSwapUsed(stats *app.Stats) *Graph {
+	swap := app.CollectorOf[*series.SwapCollector](stats, app.CollectorSwap)
+	usedSeries := []*series.Entry{
+		&swap.Used,
+		&swap.Total,
+	}
+	sub := &series.Subscriber{}
+	limit := swap.Total.Subscribe(sub)
+	data := swap.Used.Subscribe(sub)
+
+	setts := NewSettings("SwapUsed", fmtCBMem)
+	setts.GridStepCb = plot.GridStepBytes
+	setts.configName = "sys_swap_used"
+	setts.AutoMinMaxPadding = 0
+	setts.Description = "System swap used"
+
+	gr := newGraph(setts, data, usedSeries, sub)
+	gr.updateFunc = func(g *Graph) { g.Limits.Max = limit.GetFirstValue() }
+
+	return gr
+}
@@ -0,0 +1,61 @@
+package graph
+
+import (
+	"n4/gui-test/pkg/app"
+	"n4/gui-test/pkg/plot"
+	"n4/gui-test/pkg/series"
+
+	"github.com/dustin/go-humanize"
+)
+
+func init() {
+	RegisterGraphs(app.CollectorSwap, func(stats *app.Stats) []*Graph {
+		return []*Graph{
+			SysSwapIO(stats),
+			SysMajorFaults(stats),
+		}
+	})
+}
+
+func SysSwapIO(stats *app.Stats) *Graph {
+	swap := app.CollectorOf[*series.SwapCollector](stats, app.CollectorSwap)
+	sub := &series.Subscriber{}
+	datasets, usedSeries := subscribeDatasets(sub, []labeledEntry{
+		{"In", &swap.SwapIn},
+		{"Out", &swap.SwapOut},
+	})
+
+	setts := NewSettings("SwapIO", fmtCBMemRate)
+	setts.GridStepCb = plot.GridStepBytes
+	setts.configName = "sys_swap_io"
+	setts.Limits = Limits{0, humanize.MiByte}
+	// NOTE: Sustained swapping makes interactive apps stutter long before
+	// memory usage looks alarming
+	setts.Thresholds = []plot.Threshold{
+		{Value: 10 * humanize.MiByte, Level: plot.ThresholdWarning},
+		{Value: 50 * humanize.MiByte, Level: plot.ThresholdCritical},
+	}
+	setts.Description = "System swap in and out per second"
+
+	return newMultiGraph(setts, datasets, usedSeries, sub)
+}
+
+func SysMajorFaults(stats *app.Stats) *Graph {
+	swap := app.CollectorOf[*series.SwapCollector](stats, app.CollectorSwap)
+	usedSeries := []*series.Entry{
+		&swap.MajorFaults,
+	}
+	sub := &series.Subscriber{}
+	data := swap.MajorFaults.Subscribe(sub)
+
+	setts := NewSettings("MajFault/s", fmtCBFloatMaker(0))
+	setts.configName = "sys_major_faults"
+	setts.Limits = Limits{0, 10}
+	setts.Thresholds = []plot.Threshold{
+		{Value: 500, Level: plot.ThresholdWarning},
+		{Value: 2000, Level: plot.ThresholdCritical},
+	}
+	setts.Description = "System page faults read from disk per second"
+
+	return newGraph(setts, data, usedSeries, sub)
+}
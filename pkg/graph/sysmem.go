@@ -2,39 +2,56 @@ package graph
 
 import (
 	"n4/gui-test/pkg/app"
+	"n4/gui-test/pkg/plot"
 	"n4/gui-test/pkg/series"
 )
 
+func init() {
+	RegisterGraphs(app.CollectorSysMem, func(stats *app.Stats) []*Graph {
+		return []*Graph{
+			SysMemAvailable(stats),
+			SysMemUsed(stats),
+			SysMemUsedAvailable(stats),
+			SysMemUsedPercent(stats),
+		}
+	})
+}
+
 func SysMemAvailable(stats *app.Stats) *Graph {
+	sysMem := app.CollectorOf[*series.SysMemCollector](stats, app.CollectorSysMem)
 	usedSeries := []*series.Entry{
-		&stats.SysMem.Available,
-		&stats.SysMem.Total,
+		&sysMem.Available,
+		&sysMem.Total,
 	}
 	sub := &series.Subscriber{}
-	limit := stats.SysMem.Total.Subscribe(sub)
-	data := stats.SysMem.Available.Subscribe(sub)
+	limit := sysMem.Total.Subscribe(sub)
+	data := sysMem.Available.Subscribe(sub)
 
 	setts := NewSettings("MemAvail", fmtCBMem)
+	setts.GridStepCb = plot.GridStepBytes
 	setts.configName = "sys_mem_available"
 	setts.AutoMinMaxPadding = 0
 	setts.Description = "System memory available"
 
-	gr := newGraph(setts, data, usedSeries, sub)
+	gr := newGraph(setts, data, usedSeries, sub).
+		withTimeToFull(&sysMem.TimeToFull)
 	gr.updateFunc = func(g *Graph) { g.Limits.Max = limit.GetFirstValue() }
 
 	return gr
 }
 
 func SysMemUsed(stats *app.Stats) *Graph {
+	sysMem := app.CollectorOf[*series.SysMemCollector](stats, app.CollectorSysMem)
 	usedSeries := []*series.Entry{
-		&stats.SysMem.Used,
-		&stats.SysMem.Total,
+		&sysMem.Used,
+		&sysMem.Total,
 	}
 	sub := &series.Subscriber{}
-	limit := stats.SysMem.Total.Subscribe(sub)
-	data := stats.SysMem.Used.Subscribe(sub)
+	limit := sysMem.Total.Subscribe(sub)
+	data := sysMem.Used.Subscribe(sub)
 
 	setts := NewSettings("MemUsed", fmtCBMem)
+	setts.GridStepCb = plot.GridStepBytes
 	setts.configName = "sys_mem_used"
 	setts.AutoMinMaxPadding = 0
 	setts.Description = "System memory used"
@@ -45,16 +62,41 @@ func SysMemUsed(stats *app.Stats) *Graph {
 	return gr
 }
 
+func SysMemUsedAvailable(stats *app.Stats) *Graph {
+	sysMem := app.CollectorOf[*series.SysMemCollector](stats, app.CollectorSysMem)
+	sub := &series.Subscriber{}
+	datasets, usedSeries := subscribeDatasets(sub, []labeledEntry{
+		{"Used", &sysMem.Used},
+		{"Avail", &sysMem.Available},
+	})
+	limit := sysMem.Total.Subscribe(sub)
+	usedSeries = append(usedSeries, &sysMem.Total)
+
+	setts := NewSettings("Mem", fmtCBMem)
+	setts.GridStepCb = plot.GridStepBytes
+	setts.configName = "sys_mem_used_available"
+	setts.AutoMinMaxPadding = 0
+	setts.Mode = plot.ModeStacked
+	setts.Description = "System memory used and available"
+
+	gr := newMultiGraph(setts, datasets, usedSeries, sub)
+	gr.updateFunc = func(g *Graph) { g.Limits.Max = limit.GetFirstValue() }
+
+	return gr
+}
+
 func SysMemUsedPercent(stats *app.Stats) *Graph {
+	sysMem := app.CollectorOf[*series.SysMemCollector](stats, app.CollectorSysMem)
 	usedSeries := []*series.Entry{
-		&stats.SysMem.UsedPercent,
+		&sysMem.UsedPercent,
 	}
 	sub := &series.Subscriber{}
-	data := stats.SysMem.UsedPercent.Subscribe(sub)
+	data := sysMem.UsedPercent.Subscribe(sub)
 
 	setts := NewSettings("MemUsed%", fmtCBFloatMaker(2))
 	setts.configName = "sys_mem_used_percent"
 	setts.Limits = Limits{0, 100}
+	setts.GridStepCb = plot.GridStepPercent
 	setts.AutoMinMaxPadding = 0
 	setts.Description = "System memory used(percent)"
 
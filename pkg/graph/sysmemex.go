@@ -1,25 +1,37 @@
+//go:build windows || linux
+
 package graph
 
 import (
 	"n4/gui-test/pkg/app"
+	"n4/gui-test/pkg/plot"
 	"n4/gui-test/pkg/series"
 )
 
+func init() {
+	RegisterGraphs(app.CollectorSysMemEx, func(stats *app.Stats) []*Graph {
+		return []*Graph{SysMemCommit(stats)}
+	})
+}
+
 func SysMemCommit(stats *app.Stats) *Graph {
+	sysMemEx := app.CollectorOf[*series.SysMemExCollector](stats, app.CollectorSysMemEx)
 	usedSeries := []*series.Entry{
-		&stats.SysMemEx.CommitTotal,
-		&stats.SysMemEx.CommitLimit,
+		&sysMemEx.CommitTotal,
+		&sysMemEx.CommitLimit,
 	}
 	sub := &series.Subscriber{}
-	limit := stats.SysMemEx.CommitLimit.Subscribe(sub)
-	data := stats.SysMemEx.CommitTotal.Subscribe(sub)
+	limit := sysMemEx.CommitLimit.Subscribe(sub)
+	data := sysMemEx.CommitTotal.Subscribe(sub)
 
 	setts := NewSettings("MemCommit", fmtCBMem)
+	setts.GridStepCb = plot.GridStepBytes
 	setts.configName = "sys_mem_commit"
 	setts.AutoMinMaxPadding = 0
 	setts.Description = "System memory commited"
 
-	gr := newGraph(setts, data, usedSeries, sub)
+	gr := newGraph(setts, data, usedSeries, sub).
+		withTimeToFull(&sysMemEx.CommitTimeToFull)
 	gr.updateFunc = func(g *Graph) { g.Limits.Max = limit.GetFirstValue() }
 
 	return gr
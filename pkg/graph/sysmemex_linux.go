@@ -0,0 +1,101 @@
+package graph
+
+import (
+	"n4/gui-test/pkg/app"
+	"n4/gui-test/pkg/plot"
+	"n4/gui-test/pkg/series"
+
+	"github.com/dustin/go-humanize"
+)
+
+func init() {
+	RegisterGraphs(app.CollectorSysMemEx, func(stats *app.Stats) []*Graph {
+		return []*Graph{
+			SysMemDirty(stats),
+			SysMemSlab(stats),
+			SysMemCache(stats),
+			SysMemHugePages(stats),
+		}
+	})
+}
+
+func SysMemDirty(stats *app.Stats) *Graph {
+	sysMemEx := app.CollectorOf[*series.SysMemExCollector](stats, app.CollectorSysMemEx)
+	sub := &series.Subscriber{}
+	datasets, usedSeries := subscribeDatasets(sub, []labeledEntry{
+		{"Dirty", &sysMemEx.Dirty},
+		{"Writeback", &sysMemEx.Writeback},
+	})
+
+	setts := NewSettings("MemDirty", fmtCBMem)
+	setts.GridStepCb = plot.GridStepBytes
+	setts.configName = "sys_mem_dirty"
+	setts.Limits = Limits{0, humanize.MiByte}
+	setts.Mode = plot.ModeStacked
+	setts.Description = "System memory waiting to be written to disk"
+
+	return newMultiGraph(setts, datasets, usedSeries, sub)
+}
+
+func SysMemSlab(stats *app.Stats) *Graph {
+	sysMemEx := app.CollectorOf[*series.SysMemExCollector](stats, app.CollectorSysMemEx)
+	usedSeries := []*series.Entry{
+		&sysMemEx.Slab,
+	}
+	sub := &series.Subscriber{}
+	data := sysMemEx.Slab.Subscribe(sub)
+
+	setts := NewSettings("MemSlab", fmtCBMem)
+	setts.GridStepCb = plot.GridStepBytes
+	setts.configName = "sys_mem_slab"
+	setts.Limits = Limits{0, humanize.MiByte}
+	setts.Description = "System memory used by kernel slab caches"
+
+	return newGraph(setts, data, usedSeries, sub)
+}
+
+func SysMemCache(stats *app.Stats) *Graph {
+	sysMemEx := app.CollectorOf[*series.SysMemExCollector](stats, app.CollectorSysMemEx)
+	sysMem := app.CollectorOf[*series.SysMemCollector](stats, app.CollectorSysMem)
+	sub := &series.Subscriber{}
+	datasets, usedSeries := subscribeDatasets(sub, []labeledEntry{
+		{"Cached", &sysMemEx.Cached},
+		{"Buffers", &sysMemEx.Buffers},
+	})
+	limit := sysMem.Total.Subscribe(sub)
+	usedSeries = append(usedSeries, &sysMem.Total)
+
+	setts := NewSettings("MemCache", fmtCBMem)
+	setts.GridStepCb = plot.GridStepBytes
+	setts.configName = "sys_mem_cache"
+	setts.AutoMinMaxPadding = 0
+	setts.Mode = plot.ModeStacked
+	setts.Description = "System memory used by page cache and buffers"
+
+	gr := newMultiGraph(setts, datasets, usedSeries, sub)
+	gr.updateFunc = func(g *Graph) { g.Limits.Max = limit.GetFirstValue() }
+
+	return gr
+}
+
+func SysMemHugePages(stats *app.Stats) *Graph {
+	sysMemEx := app.CollectorOf[*series.SysMemExCollector](stats, app.CollectorSysMemEx)
+	usedSeries := []*series.Entry{
+		&sysMemEx.HugePagesUsed,
+		&sysMemEx.HugePagesTotal,
+	}
+	sub := &series.Subscriber{}
+	limit := sysMemEx.HugePagesTotal.Subscribe(sub)
+	data := sysMemEx.HugePagesUsed.Subscribe(sub)
+
+	setts := NewSettings("MemHuge", fmtCBMem)
+	setts.GridStepCb = plot.GridStepBytes
+	setts.configName = "sys_mem_huge_pages"
+	setts.AutoMinMaxPadding = 0
+	setts.Description = "System huge pages used"
+
+	gr := newGraph(setts, data, usedSeries, sub)
+	gr.updateFunc = func(g *Graph) { g.Limits.Max = limit.GetFirstValue() }
+
+	return gr
+}
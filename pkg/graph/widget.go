@@ -0,0 +1,50 @@
+package graph
+
+import (
+	"time"
+
+	"n4/gui-test/pkg/plot"
+)
+
+// Returns plot widget with graph data and settings applied. Size, bar size,
+// style and flags are left for the backend
+func (g *Graph) NewPlotWidget() *plot.Widget {
+	widget := plot.NewWidget(g.NameLabel, g.GetData().GetValues()).
+		SetLimits(g.Limits.Min, g.Limits.Max).
+		SetAutoHeightPadding(g.AutoMinMaxPadding).
+		SetFormatCallback(g.ValueLabelFormatCb).
+		SetGridStepCallback(g.GridStepCb).
+		SetMode(g.Mode).
+		SetThresholds(g.Thresholds...).
+		SetSampleInterval(g.GetSampleInterval()).
+		SetStale(g.IsStale(time.Now())).
+		SetError(g.GetError()).
+		SetTimeToFull(g.GetTimeToFull()).
+		SetOverlays(g.getPlotOverlays())
+
+	if len(g.datasets) > 1 {
+		wSeries := make([]plot.WidgetSeries, len(g.datasets))
+		for x, dataset := range g.datasets {
+			wSeries[x] = plot.WidgetSeries{
+				Label: dataset.Label,
+				Data:  dataset.data.GetValues(),
+			}
+		}
+		widget.SetSeries(wSeries...)
+	}
+
+	return widget
+}
+
+// Updates active graphs and returns their plot widgets
+func (gl Collection) NewPlotWidgets() []*plot.Widget {
+	widgets := make([]*plot.Widget, 0, len(gl))
+	for _, g := range gl {
+		if !g.IsActive() {
+			continue
+		}
+		g.Update()
+		widgets = append(widgets, g.NewPlotWidget())
+	}
+	return widgets
+}
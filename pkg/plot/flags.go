@@ -23,6 +23,14 @@ const (
 	// Draw latest values from right side
 	FlagsReverseOrder
 
+	// Horizontal lines at round value steps
+	FlagsGridValues
+	// Vertical lines at round time intervals
+	FlagsGridTime
+
+	// Show error text of data source instead of a badge
+	FlagsErrorText
+
 	// Draw plot outside of set widget size
 	FlagsDebugIgnoreCanvasBounds
 )
@@ -33,4 +41,6 @@ const (
 	FlagsBorderTopBottom = FlagsBorderTop | FlagsBorderBottom
 	FlagsBorderLeftRight = FlagsBorderLeft | FlagsBorderRight
 	FlagsBorderAll       = FlagsBorderTopBottom | FlagsBorderLeftRight
+
+	FlagsGridAll = FlagsGridValues | FlagsGridTime
 )
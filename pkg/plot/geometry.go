@@ -0,0 +1,131 @@
+package plot
+
+import (
+	"image"
+	"math"
+	"slices"
+
+	"n4/gui-test/internal/utils"
+	"n4/gui-test/pkg/bitflags"
+)
+
+// Open chain of points in widget coordinates
+type Polyline []image.Point
+
+// Closed chain of points in widget coordinates. The last point connects to
+// the first one
+type Polygon []image.Point
+
+func (w *Widget) limitPoint(pt image.Point) image.Point {
+	if bitflags.Has(w.Flags, FlagsDebugIgnoreCanvasBounds) {
+		return pt
+	}
+	return utils.LimitPointToRectangle(pt, image.Rect(0, 0, w.Width-1, w.Height-1))
+}
+
+type valueRun struct {
+	tops, bases Polyline
+}
+
+// Returns runs of points for consecutive finite values of series idx.
+// NaN/Inf values break the plot into separate runs
+func (w *Widget) getValueRuns(idx int) (runs []valueRun) {
+	midPoint, fracSize := w.getScale()
+	step := w.barWidth + w.barSpacing
+	toY := func(val float64) int {
+		return midPoint - int(math.Round(val/fracSize))
+	}
+
+	var run valueRun
+	for x := range w.series[idx].Data {
+		top, base, valid := w.getValueSpan(idx, x)
+		if !valid {
+			if len(run.tops) > 0 {
+				runs = append(runs, run)
+				run = valueRun{}
+			}
+			continue
+		}
+
+		pOffset := x * step
+		topY, baseY := toY(top), toY(base)
+		if w.Style == StyleStep {
+			run.tops = append(run.tops,
+				w.limitPoint(image.Pt(pOffset, topY)),
+				w.limitPoint(image.Pt(pOffset+step, topY)),
+			)
+			run.bases = append(run.bases,
+				w.limitPoint(image.Pt(pOffset, baseY)),
+				w.limitPoint(image.Pt(pOffset+step, baseY)),
+			)
+		} else {
+			pOffset += w.barWidth / 2
+			run.tops = append(run.tops, w.limitPoint(image.Pt(pOffset, topY)))
+			run.bases = append(run.bases, w.limitPoint(image.Pt(pOffset, baseY)))
+		}
+	}
+	if len(run.tops) > 0 {
+		runs = append(runs, run)
+	}
+
+	return runs
+}
+
+// Returns lines to draw over the values of series idx. Empty for styles
+// without a line
+func (w *Widget) GetPolylines(idx int) []Polyline {
+	if !w.Style.HasLine() {
+		return nil
+	}
+
+	runs := w.getValueRuns(idx)
+	lines := make([]Polyline, 0, len(runs))
+	for _, run := range runs {
+		lines = append(lines, run.tops)
+	}
+	return lines
+}
+
+// Returns areas between the values of series idx and their base. The base is
+// the midline, or the previous series in stacked mode. Empty for styles
+// without a fill
+func (w *Widget) GetPolygons(idx int) []Polygon {
+	if !w.Style.HasFill() {
+		return nil
+	}
+
+	midPoint, _ := w.getScale()
+
+	runs := w.getValueRuns(idx)
+	polygons := make([]Polygon, 0, len(runs))
+	for _, run := range runs {
+		polygon := make(Polygon, 0, len(run.tops)+len(run.bases))
+		polygon = append(polygon, run.tops...)
+		if w.Mode == ModeStacked && idx > 0 {
+			bases := slices.Clone(run.bases)
+			slices.Reverse(bases)
+			polygon = append(polygon, bases...)
+		} else {
+			first, last := run.tops[0], run.tops[len(run.tops)-1]
+			polygon = append(polygon,
+				w.limitPoint(image.Pt(last.X, midPoint)),
+				w.limitPoint(image.Pt(first.X, midPoint)),
+			)
+		}
+		polygons = append(polygons, polygon)
+	}
+
+	return polygons
+}
+
+// Returns fill intensity in range [0, 1] for row y of a gradient area. It
+// fades from the widget edge farthest from the midline towards the midline
+func (w *Widget) GetGradientRatio(y float64) float64 {
+	midPoint, _ := w.getScale()
+	mid := float64(max(min(midPoint, w.Height-1), 0))
+	maxDist := max(mid, float64(w.Height-1)-mid)
+	if maxDist <= 0 {
+		return 1
+	}
+	return max(min(math.Abs(y-mid)/maxDist, 1), 0)
+}
@@ -0,0 +1,238 @@
+package plot
+
+import (
+	"image"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestWidget(style Style, data WidgetData) *Widget {
+	return NewWidget("test", data).
+		SetSize(20, 11).
+		SetLimits(0, 10).
+		SetBarSize(2, 1).
+		SetFlags(FlagsNone, true).
+		SetStyle(style)
+}
+
+func TestWidget_GetPolylines(t *testing.T) {
+	tests := []struct {
+		name  string
+		style Style
+		data  WidgetData
+		want  []Polyline
+	}{
+		{
+			name:  "Bar has no lines",
+			style: StyleBar,
+			data:  WidgetData{1, 2, 3},
+			want:  nil,
+		},
+		{
+			name:  "Line",
+			style: StyleLine,
+			data:  WidgetData{0, 5, 10},
+			want: []Polyline{
+				{image.Pt(1, 10), image.Pt(4, 5), image.Pt(7, 0)},
+			},
+		},
+		{
+			name:  "Line split by NaN and Inf",
+			style: StyleLine,
+			data:  WidgetData{1, math.NaN(), 2, 3, math.Inf(1), 4},
+			want: []Polyline{
+				{image.Pt(1, 9)},
+				{image.Pt(7, 8), image.Pt(10, 7)},
+				{image.Pt(16, 6)},
+			},
+		},
+		{
+			name:  "Line clamped to widget",
+			style: StyleLine,
+			data:  WidgetData{-5, 20},
+			want: []Polyline{
+				{image.Pt(1, 10), image.Pt(4, 0)},
+			},
+		},
+		{
+			name:  "Step",
+			style: StyleStep,
+			data:  WidgetData{2, 4},
+			want: []Polyline{
+				{image.Pt(0, 8), image.Pt(3, 8), image.Pt(3, 6), image.Pt(6, 6)},
+			},
+		},
+		{
+			name:  "Area has outline",
+			style: StyleArea,
+			data:  WidgetData{2, 4},
+			want: []Polyline{
+				{image.Pt(1, 8), image.Pt(4, 6)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newTestWidget(tt.style, tt.data).GetPolylines(0)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWidget_GetPolylines_ReverseOrder(t *testing.T) {
+	w := newTestWidget(StyleLine, WidgetData{0, 5, 10}).
+		SetFlags(FlagsReverseOrder, false)
+	want := []Polyline{
+		{image.Pt(1, 0), image.Pt(4, 5), image.Pt(7, 10)},
+	}
+	assert.Equal(t, want, w.GetPolylines(0))
+}
+
+func TestWidget_GetPolygons(t *testing.T) {
+	tests := []struct {
+		name  string
+		style Style
+		data  WidgetData
+		want  []Polygon
+	}{
+		{
+			name:  "Line has no polygons",
+			style: StyleLine,
+			data:  WidgetData{1, 2},
+			want:  nil,
+		},
+		{
+			name:  "Area",
+			style: StyleArea,
+			data:  WidgetData{2, 4},
+			want: []Polygon{
+				{image.Pt(1, 8), image.Pt(4, 6), image.Pt(4, 10), image.Pt(1, 10)},
+			},
+		},
+		{
+			name:  "Gradient area split by NaN",
+			style: StyleGradientArea,
+			data:  WidgetData{2, math.NaN(), 4},
+			want: []Polygon{
+				{image.Pt(1, 8), image.Pt(1, 10), image.Pt(1, 10)},
+				{image.Pt(7, 6), image.Pt(7, 10), image.Pt(7, 10)},
+			},
+		},
+		{
+			name:  "All values invalid",
+			style: StyleArea,
+			data:  WidgetData{math.NaN(), math.Inf(-1)},
+			want:  []Polygon{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newTestWidget(tt.style, tt.data).GetPolygons(0)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWidget_GetGradientRatio(t *testing.T) {
+	w := newTestWidget(StyleGradientArea, WidgetData{0})
+	assert.InDelta(t, 0.0, w.GetGradientRatio(10), 1e-9)
+	assert.InDelta(t, 0.5, w.GetGradientRatio(5), 1e-9)
+	assert.InDelta(t, 1.0, w.GetGradientRatio(0), 1e-9)
+	assert.InDelta(t, 1.0, w.GetGradientRatio(-5), 1e-9)
+
+	w.SetLimits(-10, 10).SetSize(20, 21)
+	assert.InDelta(t, 0.0, w.GetGradientRatio(10), 1e-9)
+	assert.InDelta(t, 1.0, w.GetGradientRatio(0), 1e-9)
+	assert.InDelta(t, 1.0, w.GetGradientRatio(20), 1e-9)
+}
+
+func TestParseStyle(t *testing.T) {
+	for _, style := range []Style{
+		StyleBar, StyleLine, StyleArea, StyleStep, StyleGradientArea,
+	} {
+		t.Run(style.String(), func(t *testing.T) {
+			got, err := ParseStyle(style.String())
+			assert.NoError(t, err)
+			assert.Equal(t, style, got)
+		})
+	}
+
+	_, err := ParseStyle("pie")
+	assert.Error(t, err)
+	assert.Equal(t, StyleBar, StyleGradientArea.Next())
+}
+
+func TestWidget_GetValueRect_Stacked(t *testing.T) {
+	w := newTestWidget(StyleBar, nil).
+		SetSeries(
+			WidgetSeries{Label: "a", Data: WidgetData{2, math.NaN()}},
+			WidgetSeries{Label: "b", Data: WidgetData{3, 4}},
+		).
+		SetMode(ModeStacked)
+
+	assert.Equal(t, image.Rect(0, 8, 2, 10), w.GetValueRect(0, 0))
+	assert.Equal(t, image.Rect(0, 5, 2, 8), w.GetValueRect(1, 0))
+	assert.Equal(t, image.Rectangle{}, w.GetValueRect(0, 1))
+	assert.Equal(t, image.Rect(3, 6, 5, 10), w.GetValueRect(1, 1))
+}
+
+func TestWidget_GetPolygons_Stacked(t *testing.T) {
+	w := newTestWidget(StyleArea, nil).
+		SetSeries(
+			WidgetSeries{Label: "a", Data: WidgetData{2, 2}},
+			WidgetSeries{Label: "b", Data: WidgetData{3, 4}},
+		).
+		SetMode(ModeStacked)
+
+	assert.Equal(t, []Polyline{
+		{image.Pt(1, 5), image.Pt(4, 4)},
+	}, w.GetPolylines(1))
+	assert.Equal(t, []Polygon{
+		{image.Pt(1, 5), image.Pt(4, 4), image.Pt(4, 8), image.Pt(1, 8)},
+	}, w.GetPolygons(1))
+}
+
+func TestWidget_GetSanitizedMinMax_Modes(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    Mode
+		wantMin float64
+		wantMax float64
+	}{
+		{name: "Overlay", mode: ModeOverlay, wantMin: 1, wantMax: 4},
+		{name: "Stacked", mode: ModeStacked, wantMin: 1, wantMax: 6},
+		{name: "Mirrored", mode: ModeMirrored, wantMin: -4, wantMax: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := newTestWidget(StyleLine, nil).
+				SetSeries(
+					WidgetSeries{Label: "a", Data: WidgetData{1, 2}},
+					WidgetSeries{Label: "b", Data: WidgetData{math.NaN(), 4}},
+				).
+				SetMode(tt.mode).
+				SetFlags(FlagsAutoMinMax, true).
+				SetAutoHeightPadding(0)
+
+			gotMin, gotMax := w.GetSanitizedMinMax()
+			assert.InDelta(t, tt.wantMin, gotMin, 1e-9)
+			assert.InDelta(t, tt.wantMax, gotMax, 1e-9)
+		})
+	}
+}
+
+func TestWidget_GetPolylines_Mirrored(t *testing.T) {
+	w := newTestWidget(StyleLine, nil).
+		SetLimits(-10, 10).
+		SetSize(20, 21).
+		SetSeries(
+			WidgetSeries{Label: "rx", Data: WidgetData{5}},
+			WidgetSeries{Label: "tx", Data: WidgetData{5}},
+		).
+		SetMode(ModeMirrored)
+
+	assert.Equal(t, []Polyline{{image.Pt(1, 5)}}, w.GetPolylines(0))
+	assert.Equal(t, []Polyline{{image.Pt(1, 15)}}, w.GetPolylines(1))
+}
@@ -0,0 +1,172 @@
+package plot
+
+import (
+	"image"
+	"math"
+	"slices"
+	"time"
+
+	"n4/gui-test/pkg/bitflags"
+)
+
+const (
+	// Minimal distance in pixels between neighbour grid lines
+	gridMinSpacing = 8
+	// Minimal distance in pixels between neighbour time ticks
+	tickMinSpacing = 20
+)
+
+// Returns distance between grid lines for value range split into at most
+// maxLines parts
+type StepCallback func(valueRange float64, maxLines int) float64
+
+// Steps of 1, 2 or 5 multiplied by power of 10
+func GridStepDecimal(valueRange float64, maxLines int) float64 {
+	raw := rawStep(valueRange, maxLines)
+	if raw == 0 {
+		return 0
+	}
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+	for _, mult := range []float64{1, 2, 5} {
+		if mult*magnitude >= raw {
+			return mult * magnitude
+		}
+	}
+	return 10 * magnitude
+}
+
+// Steps of power of 2, e.g. 512 MiB, 1 GiB, 2 GiB
+func GridStepBytes(valueRange float64, maxLines int) float64 {
+	raw := rawStep(valueRange, maxLines)
+	if raw == 0 {
+		return 0
+	}
+	return math.Pow(2, math.Ceil(math.Log2(raw)))
+}
+
+// Steps of 1%, 5%, 10%, 25%, 50% or 100%
+func GridStepPercent(valueRange float64, maxLines int) float64 {
+	raw := rawStep(valueRange, maxLines)
+	if raw == 0 {
+		return 0
+	}
+	for _, step := range []float64{1, 5, 10, 25, 50} {
+		if step >= raw {
+			return step
+		}
+	}
+	return GridStepDecimal(valueRange, maxLines)
+}
+
+func rawStep(valueRange float64, maxLines int) float64 {
+	if maxLines < 1 || !(valueRange > 0) || math.IsInf(valueRange, 0) {
+		return 0
+	}
+	return valueRange / float64(maxLines)
+}
+
+var timeTickIntervals = []time.Duration{
+	time.Second,
+	5 * time.Second,
+	10 * time.Second,
+	15 * time.Second,
+	30 * time.Second,
+	time.Minute,
+	2 * time.Minute,
+	5 * time.Minute,
+	10 * time.Minute,
+	15 * time.Minute,
+	30 * time.Minute,
+	time.Hour,
+	2 * time.Hour,
+	6 * time.Hour,
+	12 * time.Hour,
+	24 * time.Hour,
+}
+
+// Returns values of horizontal grid lines in the plot range. Midline value
+// is excluded
+func (w *Widget) GetGridValues() []float64 {
+	xMin, xMax := w.GetSanitizedMinMax()
+	maxLines := w.Height / gridMinSpacing
+	step := w.GridStepCallback(xMax-xMin, maxLines)
+	if !(step > 0) || math.IsInf(step, 0) {
+		return nil
+	}
+
+	values := make([]float64, 0, maxLines+1)
+	for mult := math.Ceil(xMin / step); mult*step <= xMax; mult++ {
+		if len(values) > maxLines {
+			break
+		}
+		if mult == 0 {
+			continue
+		}
+		values = append(values, mult*step)
+	}
+	return values
+}
+
+// Returns horizontal grid lines at round value steps
+func (w *Widget) GetGridLines() (lines []image.Rectangle) {
+	if !bitflags.Has(w.Flags, FlagsGridValues) {
+		return nil
+	}
+
+	midPoint, fracSize := w.getScale()
+	for _, val := range w.GetGridValues() {
+		y := midPoint - int(math.Round(val/fracSize))
+		if y < 0 || y >= w.Height || y == midPoint {
+			continue
+		}
+		lines = append(lines, image.Rect(0, y, w.Width, y+1))
+	}
+	return lines
+}
+
+// Returns the shortest round time interval with ticks far enough from each
+// other. Zero if sample interval is not set
+func (w *Widget) GetTimeTickInterval() time.Duration {
+	if w.sampleInterval <= 0 {
+		return 0
+	}
+	step := w.barWidth + w.barSpacing
+	idx := slices.IndexFunc(timeTickIntervals, func(interval time.Duration) bool {
+		if interval%w.sampleInterval != 0 {
+			return false
+		}
+		samples := int(interval / w.sampleInterval)
+		return samples*step >= tickMinSpacing
+	})
+	if idx < 0 {
+		return 0
+	}
+	return timeTickIntervals[idx]
+}
+
+// Returns vertical lines at round time intervals counted from the latest value
+func (w *Widget) GetTimeTicks() (ticks []image.Rectangle) {
+	if !bitflags.Has(w.Flags, FlagsGridTime) {
+		return nil
+	}
+	interval := w.GetTimeTickInterval()
+	if interval == 0 {
+		return nil
+	}
+
+	dataLen := len(w.GetData())
+	samples := int(interval / w.sampleInterval)
+	step := w.barWidth + w.barSpacing
+	for age := samples; age < dataLen; age += samples {
+		x := age
+		if bitflags.Has(w.Flags, FlagsReverseOrder) {
+			x = dataLen - 1 - age
+		}
+		pOffset := x*step + w.barWidth/2
+		if pOffset < 0 || pOffset >= w.Width {
+			continue
+		}
+		ticks = append(ticks, image.Rect(pOffset, 0, pOffset+1, w.Height))
+	}
+	return ticks
+}
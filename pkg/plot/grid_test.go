@@ -0,0 +1,126 @@
+package plot
+
+import (
+	"fmt"
+	"image"
+	"testing"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGridStep(t *testing.T) {
+	tests := []struct {
+		name       string
+		cb         StepCallback
+		valueRange float64
+		maxLines   int
+		want       float64
+	}{
+		{name: "Decimal 1", cb: GridStepDecimal, valueRange: 10, maxLines: 10, want: 1},
+		{name: "Decimal 2", cb: GridStepDecimal, valueRange: 15, maxLines: 10, want: 2},
+		{name: "Decimal 5", cb: GridStepDecimal, valueRange: 0.3, maxLines: 10, want: 0.05},
+		{name: "Decimal 10", cb: GridStepDecimal, valueRange: 70, maxLines: 10, want: 10},
+		{name: "Decimal zero range", cb: GridStepDecimal, valueRange: 0, maxLines: 10, want: 0},
+		{name: "Decimal zero lines", cb: GridStepDecimal, valueRange: 10, maxLines: 0, want: 0},
+		{
+			name: "Bytes 1 GiB", cb: GridStepBytes,
+			valueRange: 16 * humanize.GiByte, maxLines: 16, want: humanize.GiByte,
+		},
+		{
+			name: "Bytes 2 GiB", cb: GridStepBytes,
+			valueRange: 20 * humanize.GiByte, maxLines: 16, want: 2 * humanize.GiByte,
+		},
+		{
+			name: "Bytes 512 MiB", cb: GridStepBytes,
+			valueRange: 3 * humanize.GiByte, maxLines: 8, want: 512 * humanize.MiByte,
+		},
+		{name: "Percent 25", cb: GridStepPercent, valueRange: 100, maxLines: 4, want: 25},
+		{name: "Percent 10", cb: GridStepPercent, valueRange: 100, maxLines: 10, want: 10},
+		{name: "Percent 50", cb: GridStepPercent, valueRange: 100, maxLines: 3, want: 50},
+		{name: "Percent 100", cb: GridStepPercent, valueRange: 200, maxLines: 2, want: 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, tt.cb(tt.valueRange, tt.maxLines), 1e-9)
+		})
+	}
+}
+
+func TestWidget_GetGridLines(t *testing.T) {
+	w := NewWidget("test", WidgetData{0}).
+		SetSize(10, 41).
+		SetLimits(0, 100).
+		SetFlags(FlagsGridValues, true).
+		SetGridStepCallback(GridStepPercent)
+
+	assert.Equal(t, []float64{25, 50, 75, 100}, w.GetGridValues())
+	assert.Equal(t, []image.Rectangle{
+		image.Rect(0, 30, 10, 31),
+		image.Rect(0, 20, 10, 21),
+		image.Rect(0, 10, 10, 11),
+		image.Rect(0, 0, 10, 1),
+	}, w.GetGridLines())
+
+	w.SetLimits(-50, 50)
+	assert.Equal(t, []float64{-50, -25, 25, 50}, w.GetGridValues())
+
+	w.ClearFlags(FlagsGridValues)
+	assert.Empty(t, w.GetGridLines())
+}
+
+func TestWidget_GetTimeTickInterval(t *testing.T) {
+	tests := []struct {
+		sample   time.Duration
+		barWidth int
+		spacing  int
+		want     time.Duration
+	}{
+		{sample: 0, barWidth: 1, want: 0},
+		{sample: time.Second, barWidth: 1, want: 30 * time.Second},
+		{sample: time.Second, barWidth: 2, spacing: 1, want: 10 * time.Second},
+		{sample: time.Second, barWidth: 6, spacing: 6, want: 5 * time.Second},
+		{sample: 2 * time.Second, barWidth: 1, want: time.Minute},
+		{sample: 7 * time.Second, barWidth: 1, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.sample, tt.barWidth, tt.spacing), func(t *testing.T) {
+			w := NewWidget("test", WidgetData{0}).
+				SetBarSize(tt.barWidth, tt.spacing).
+				SetSampleInterval(tt.sample)
+			assert.Equal(t, tt.want, w.GetTimeTickInterval())
+		})
+	}
+}
+
+func TestWidget_GetTimeTicks(t *testing.T) {
+	w := NewWidget("test", make(WidgetData, 100)).
+		SetSize(100, 10).
+		SetBarSize(1, 0).
+		SetSampleInterval(time.Second).
+		SetFlags(FlagsGridTime|FlagsReverseOrder, true)
+
+	assert.Equal(t, []image.Rectangle{
+		image.Rect(69, 0, 70, 10),
+		image.Rect(39, 0, 40, 10),
+		image.Rect(9, 0, 10, 10),
+	}, w.GetTimeTicks())
+
+	w.SetFlags(FlagsGridTime, true)
+	assert.Equal(t, []image.Rectangle{
+		image.Rect(30, 0, 31, 10),
+		image.Rect(60, 0, 61, 10),
+		image.Rect(90, 0, 91, 10),
+	}, w.GetTimeTicks())
+}
+
+func TestWidget_SetDefaultSampleInterval(t *testing.T) {
+	w := NewWidget("test", WidgetData{0}).SetBarSize(1, 0)
+
+	w.SetDefaultSampleInterval(time.Second)
+	assert.Equal(t, 30*time.Second, w.GetTimeTickInterval())
+
+	w.SetSampleInterval(10 * time.Second).SetDefaultSampleInterval(time.Second)
+	assert.Equal(t, 5*time.Minute, w.GetTimeTickInterval())
+}
@@ -0,0 +1,147 @@
+package plot
+
+import (
+	"fmt"
+	"image"
+	"math"
+
+	"n4/gui-test/pkg/bitflags"
+)
+
+const (
+	// Appended to name label of stale widget
+	StaleSuffix = " (stale)"
+	// Shown next to name label of widget with error
+	ErrorBadge = "ERR"
+	// Precedes time to full shown after value labels
+	TimeToFullPrefix = "full in "
+)
+
+type Label struct {
+	Text string
+	Pos  image.Point
+
+	// Series the label belongs to. -1 for labels common for all series
+	SeriesIdx int
+	// Error badge or text, drawn with critical color
+	Error bool
+}
+
+// Returns name, min/max and value labels in widget coordinates. Single series
+// widget gets plain value label, multiple series get a legend with values.
+// Rolling mean/p95 and time to full follow values
+func (w *Widget) GetLabels(textWidth func(text string) int, lineHeight int) []Label {
+	if !bitflags.Has(w.Flags, FlagsLabelsAll) {
+		return nil
+	}
+
+	xMin, xMax := w.GetSanitizedMinMax()
+	maxText, minText := w.FormatCallback(xMax), w.FormatCallback(xMin)
+
+	name := w.Label
+	if w.Stale {
+		name += StaleSuffix
+	}
+
+	labels := []Label{
+		{
+			Text:      name,
+			Pos:       w.LabelPadding,
+			SeriesIdx: -1,
+		},
+		{
+			Text: maxText,
+			Pos: image.Pt(
+				w.Width-textWidth(maxText)-w.LabelPadding.X,
+				w.LabelPadding.Y,
+			),
+			SeriesIdx: -1,
+		},
+		{
+			Text: minText,
+			Pos: image.Pt(
+				w.Width-textWidth(minText)-w.LabelPadding.X,
+				w.Height-lineHeight-w.LabelPadding.Y,
+			),
+			SeriesIdx: -1,
+		},
+	}
+
+	if w.Error != "" {
+		labels = append(labels, w.getErrorLabel(labels[0], labels[1], textWidth))
+	}
+
+	legendPos := image.Pt(w.LabelPadding.X, w.LabelPadding.Y+lineHeight)
+	for idx, wSeries := range w.series {
+		if len(wSeries.Data) == 0 {
+			continue
+		}
+		label := Label{
+			Text:      w.FormatCallback(wSeries.Data[0]),
+			Pos:       legendPos,
+			SeriesIdx: idx,
+		}
+		if len(w.series) > 1 {
+			label.Text = wSeries.Label + " " + label.Text
+		}
+		labels = append(labels, label)
+		legendPos.X += textWidth(label.Text) + w.LabelPadding.X
+	}
+
+	if text := w.getOverlayLabelText(); text != "" {
+		labels = append(labels, Label{Text: text, Pos: legendPos, SeriesIdx: -1})
+		legendPos.X += textWidth(text) + w.LabelPadding.X
+	}
+
+	if !math.IsNaN(w.TimeToFull) && !math.IsInf(w.TimeToFull, 1) {
+		labels = append(labels, Label{
+			Text:      TimeToFullPrefix + FormatTimeToFull(w.TimeToFull),
+			Pos:       legendPos,
+			SeriesIdx: -1,
+		})
+	}
+
+	return labels
+}
+
+// Returns seconds until full rounded to its two largest units, i.e. 2h5m.
+// Time is capped at 999 days
+func FormatTimeToFull(seconds float64) string {
+	const maxDays = 999
+	secs := int64(max(0, min(seconds, maxDays*24*3600)))
+	switch {
+	case secs < 60:
+		return fmt.Sprintf("%ds", secs)
+	case secs < 3600:
+		return fmt.Sprintf("%dm%ds", secs/60, secs%60)
+	case secs < 24*3600:
+		return fmt.Sprintf("%dh%dm", secs/3600, secs%3600/60)
+	}
+	return fmt.Sprintf("%dd%dh", secs/(24*3600), secs%(24*3600)/3600)
+}
+
+// Returns error badge placed between name and max labels. Error text is cut
+// to fit there
+func (w *Widget) getErrorLabel(
+	name, maxLabel Label, textWidth func(text string) int,
+) Label {
+	pos := name.Pos.Add(image.Pt(textWidth(name.Text)+w.LabelPadding.X, 0))
+	text := ErrorBadge
+	if bitflags.Has(w.Flags, FlagsErrorText) {
+		width := maxLabel.Pos.X - w.LabelPadding.X - pos.X
+		text = fitText(ErrorBadge+": "+w.Error, width, textWidth)
+	}
+	return Label{Text: text, Pos: pos, SeriesIdx: -1, Error: true}
+}
+
+// Returns text cut to width with ellipsis
+func fitText(text string, width int, textWidth func(text string) int) string {
+	if textWidth(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && textWidth(string(runes)+"…") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
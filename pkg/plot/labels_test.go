@@ -0,0 +1,81 @@
+package plot
+
+import (
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWidget_GetLabels_Stale(t *testing.T) {
+	textWidth := func(text string) int { return len(text) }
+	w := NewWidget("CPU", WidgetData{1, 2})
+
+	assert.Equal(t, "CPU", w.GetLabels(textWidth, 1)[0].Text)
+
+	w.SetStale(true)
+	assert.Equal(t, "CPU"+StaleSuffix, w.GetLabels(textWidth, 1)[0].Text)
+}
+
+func TestWidget_GetLabels_Error(t *testing.T) {
+	textWidth := func(text string) int { return len([]rune(text)) }
+	w := NewWidget("CPU", WidgetData{1}).
+		SetSize(24, 10).
+		SetLimits(0, 1).
+		SetFlags(FlagsLabelsAll, true).
+		SetError("permission denied")
+	w.LabelPadding = image.Pt(1, 0)
+
+	errorLabel := func() Label {
+		for _, label := range w.GetLabels(textWidth, 1) {
+			if label.Error {
+				return label
+			}
+		}
+		return Label{}
+	}
+
+	assert.Equal(t, Label{
+		Text: ErrorBadge, Pos: image.Pt(5, 0), SeriesIdx: -1, Error: true,
+	}, errorLabel())
+
+	w.SetFlags(FlagsErrorText, false)
+	assert.Equal(t, "ERR: permission…", errorLabel().Text)
+
+	w.SetError("")
+	assert.Equal(t, Label{}, errorLabel())
+}
+
+func TestWidget_GetLabels_TimeToFull(t *testing.T) {
+	textWidth := func(text string) int { return len(text) }
+	w := NewWidget("Free", WidgetData{1})
+	w.LabelPadding = image.Pt(1, 0)
+
+	labels := w.GetLabels(textWidth, 1)
+	assert.NotContains(t, labels[len(labels)-1].Text, TimeToFullPrefix)
+
+	w.SetTimeToFull(90)
+	labels = w.GetLabels(textWidth, 1)
+	assert.Equal(t, Label{
+		Text: TimeToFullPrefix + "1m30s", Pos: image.Pt(3, 1), SeriesIdx: -1,
+	}, labels[len(labels)-1])
+}
+
+func TestFormatTimeToFull(t *testing.T) {
+	tests := []struct {
+		seconds float64
+		want    string
+	}{
+		{0, "0s"},
+		{59.9, "59s"},
+		{90, "1m30s"},
+		{2*3600 + 5*60 + 10, "2h5m"},
+		{3*24*3600 + 4*3600, "3d4h"},
+		{1e12, "999d0h"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, FormatTimeToFull(tt.seconds))
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package plot
+
+// Defines how multiple series of a widget share the plot
+type Mode int
+
+const (
+	// Series are drawn on top of each other from the midline
+	ModeOverlay Mode = iota
+
+	// Each series is drawn on top of the sum of the previous ones
+	ModeStacked
+
+	// First series is drawn above the midline, the rest below it
+	ModeMirrored
+)
@@ -0,0 +1,156 @@
+package plot
+
+import (
+	"image"
+	"math"
+	"slices"
+
+	"n4/gui-test/pkg/bitflags"
+)
+
+const (
+	// Precede rolling mean and p95 shown after value labels
+	MeanPrefix = "avg "
+	P95Prefix  = "p95 "
+)
+
+// Statistics of plotted values over a rolling window, aligned with series
+// data. Nil data is not drawn
+type Overlays struct {
+	Mean WidgetData
+	P95  WidgetData
+	// Envelope between the lowest and the highest values
+	Min, Max WidgetData
+}
+
+// Returns overlays of the latest samples only
+func (o Overlays) Head(samples int) Overlays {
+	head := func(data WidgetData) WidgetData {
+		if data == nil {
+			return nil
+		}
+		// NOTE: The latest value is the first one
+		return data[:min(samples, len(data))]
+	}
+	return Overlays{
+		Mean: head(o.Mean),
+		P95:  head(o.P95),
+		Min:  head(o.Min),
+		Max:  head(o.Max),
+	}
+}
+
+type OverlayKind int
+
+const (
+	OverlayMean OverlayKind = iota
+	OverlayP95
+)
+
+// Line of rolling statistic in widget coordinates
+type OverlayLine struct {
+	Line Polyline
+	Kind OverlayKind
+}
+
+func (w *Widget) SetOverlays(overlays Overlays) *Widget {
+	w.overlays = overlays
+	return w
+}
+
+func (w *Widget) GetOverlays() Overlays {
+	return w.overlays
+}
+
+func (w *Widget) getDataValue(data WidgetData, x int) float64 {
+	if bitflags.Has(w.Flags, FlagsReverseOrder) {
+		return data[len(data)-1-x]
+	}
+	return data[x]
+}
+
+// Returns runs of points at the middle of bars for consecutive finite values
+// of data
+func (w *Widget) getDataRuns(data WidgetData) (runs []Polyline) {
+	midPoint, fracSize := w.getScale()
+	step := w.barWidth + w.barSpacing
+
+	var run Polyline
+	for x := range data {
+		val := w.getDataValue(data, x)
+		if !isFinite(val) {
+			if len(run) > 0 {
+				runs = append(runs, run)
+				run = nil
+			}
+			continue
+		}
+		y := midPoint - int(math.Round(val/fracSize))
+		run = append(run, w.limitPoint(image.Pt(x*step+w.barWidth/2, y)))
+	}
+	if len(run) > 0 {
+		runs = append(runs, run)
+	}
+	return runs
+}
+
+// Returns lines of rolling mean and p95, mean first
+func (w *Widget) GetOverlayLines() (lines []OverlayLine) {
+	for kind, data := range []WidgetData{
+		OverlayMean: w.overlays.Mean,
+		OverlayP95:  w.overlays.P95,
+	} {
+		for _, line := range w.getDataRuns(data) {
+			lines = append(lines, OverlayLine{Line: line, Kind: OverlayKind(kind)})
+		}
+	}
+	return lines
+}
+
+// Returns areas between rolling min and max. Empty unless both are set
+func (w *Widget) GetEnvelope() []Polygon {
+	lows, highs := w.overlays.Min, w.overlays.Max
+	if len(lows) == 0 || len(lows) != len(highs) {
+		return nil
+	}
+
+	// NOTE: Envelope is broken where either bound is missing, so runs of
+	// both bounds match
+	lows, highs = slices.Clone(lows), slices.Clone(highs)
+	for x := range lows {
+		if !isFinite(lows[x]) || !isFinite(highs[x]) {
+			lows[x], highs[x] = math.NaN(), math.NaN()
+		}
+	}
+	lowRuns, highRuns := w.getDataRuns(lows), w.getDataRuns(highs)
+
+	polygons := make([]Polygon, 0, len(highRuns))
+	for x, high := range highRuns {
+		low := lowRuns[x]
+		slices.Reverse(low)
+		polygons = append(polygons, slices.Concat(Polygon(high), Polygon(low)))
+	}
+	return polygons
+}
+
+// Returns label with the latest rolling mean and p95, empty if neither is
+// set
+func (w *Widget) getOverlayLabelText() string {
+	var text string
+	for _, stat := range []struct {
+		prefix string
+		data   WidgetData
+	}{
+		{MeanPrefix, w.overlays.Mean},
+		{P95Prefix, w.overlays.P95},
+	} {
+		if len(stat.data) == 0 {
+			continue
+		}
+		if text != "" {
+			text += " "
+		}
+		text += stat.prefix + w.FormatCallback(stat.data[0])
+	}
+	return text
+}
@@ -0,0 +1,109 @@
+package plot
+
+import (
+	"image"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWidget_GetOverlayLines(t *testing.T) {
+	w := newTestWidget(StyleBar, WidgetData{1, 2, 3}).SetOverlays(Overlays{
+		Mean: WidgetData{2, math.NaN(), 4},
+		P95:  WidgetData{5, 6, 7},
+	})
+
+	assert.Equal(t, []OverlayLine{
+		{Line: Polyline{image.Pt(1, 8)}, Kind: OverlayMean},
+		{Line: Polyline{image.Pt(7, 6)}, Kind: OverlayMean},
+		{Line: Polyline{image.Pt(1, 5), image.Pt(4, 4), image.Pt(7, 3)}, Kind: OverlayP95},
+	}, w.GetOverlayLines())
+
+	w.SetOverlays(Overlays{})
+	assert.Empty(t, w.GetOverlayLines())
+}
+
+func TestWidget_GetEnvelope(t *testing.T) {
+	tests := []struct {
+		name     string
+		overlays Overlays
+		want     []Polygon
+	}{
+		{
+			name:     "No bounds",
+			overlays: Overlays{Mean: WidgetData{1}},
+			want:     nil,
+		},
+		{
+			name: "Single bound",
+			overlays: Overlays{
+				Max: WidgetData{1, 2},
+			},
+			want: nil,
+		},
+		{
+			name: "Envelope",
+			overlays: Overlays{
+				Min: WidgetData{1, 2},
+				Max: WidgetData{5, 6},
+			},
+			want: []Polygon{
+				{image.Pt(1, 5), image.Pt(4, 4), image.Pt(4, 8), image.Pt(1, 9)},
+			},
+		},
+		{
+			name: "Broken by missing bound",
+			overlays: Overlays{
+				Min: WidgetData{1, 2, math.NaN(), 3},
+				Max: WidgetData{5, math.NaN(), 6, 7},
+			},
+			want: []Polygon{
+				{image.Pt(1, 5), image.Pt(1, 9)},
+				{image.Pt(10, 3), image.Pt(10, 7)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := newTestWidget(StyleBar, WidgetData{0, 0, 0, 0}).
+				SetOverlays(tt.overlays)
+			assert.Equal(t, tt.want, w.GetEnvelope())
+		})
+	}
+}
+
+func TestWidget_GetLabels_Overlays(t *testing.T) {
+	textWidth := func(text string) int { return len(text) }
+	w := NewWidget("CPU", WidgetData{1, 2})
+	w.LabelPadding = image.Pt(1, 0)
+
+	labels := w.GetLabels(textWidth, 1)
+	assert.Equal(t, "1", labels[len(labels)-1].Text)
+
+	w.SetOverlays(Overlays{P95: WidgetData{4, 2}})
+	labels = w.GetLabels(textWidth, 1)
+	assert.Equal(t, Label{
+		Text: P95Prefix + "4", Pos: image.Pt(3, 1), SeriesIdx: -1,
+	}, labels[len(labels)-1])
+
+	w.SetOverlays(Overlays{Mean: WidgetData{1.5, 2}, P95: WidgetData{4, 2}}).
+		SetTimeToFull(90)
+	labels = w.GetLabels(textWidth, 1)
+	assert.Equal(t, Label{
+		Text: MeanPrefix + "1.5 " + P95Prefix + "4", Pos: image.Pt(3, 1), SeriesIdx: -1,
+	}, labels[len(labels)-2])
+	assert.Equal(t, image.Pt(17, 1), labels[len(labels)-1].Pos)
+}
+
+func TestOverlays_Head(t *testing.T) {
+	overlays := Overlays{
+		Mean: WidgetData{1, 2, 3},
+		Min:  WidgetData{4, 5, 6},
+	}
+	assert.Equal(t, Overlays{
+		Mean: WidgetData{1, 2},
+		Min:  WidgetData{4, 5},
+	}, overlays.Head(2))
+	assert.Equal(t, overlays, overlays.Head(5))
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"image"
 	"math"
+	"time"
 
 	"n4/gui-test/internal/utils"
 	"n4/gui-test/pkg/bitflags"
@@ -13,18 +14,23 @@ type FormatCallback func(value float64) string
 
 type WidgetData []float64
 
+type WidgetSeries struct {
+	Label string
+	Data  WidgetData
+}
+
 type Widget struct {
 	Label string
 
-	data WidgetData
+	series []WidgetSeries
+	Mode   Mode
 
 	// TODO: handle zero Width, Height
 	Width, Height     int
 	xMin, xMax        float64
 	autoMinMaxPadding float64
 
-	// TODO: Implement threshold line and color
-	// thresholds []Threshold
+	thresholds []Threshold
 
 	barWidth, barSpacing int
 
@@ -32,13 +38,30 @@ type Widget struct {
 
 	FormatCallback FormatCallback
 
+	GridStepCallback StepCallback
+
+	// Time between neighbour values
+	sampleInterval time.Duration
+
+	// Data source got no values lately, marked in name label
+	Stale bool
+	// Error of data source, shown next to name label
+	Error string
+	// Seconds until plotted value reaches its limit at its trend, i.e. disk
+	// gets full. Shown after value labels, NaN and +Inf hide it
+	TimeToFull float64
+
+	overlays Overlays
+
 	Flags Flag
+
+	Style Style
 }
 
 func NewWidget(label string, data WidgetData) *Widget {
 	return &Widget{
 		Label:  label,
-		data:   data,
+		series: []WidgetSeries{{Label: label, Data: data}},
 		Width:  200,
 		Height: 100,
 
@@ -47,6 +70,8 @@ func NewWidget(label string, data WidgetData) *Widget {
 
 		autoMinMaxPadding: 0.1,
 
+		TimeToFull: math.NaN(),
+
 		barWidth:   4,
 		barSpacing: 2,
 
@@ -56,10 +81,15 @@ func NewWidget(label string, data WidgetData) *Widget {
 			return fmt.Sprint(value)
 		},
 
+		GridStepCallback: GridStepDecimal,
+
 		Flags: FlagsAutoMinMax |
 			FlagsBorderAll |
 			FlagsLabelsAll |
-			FlagsReverseOrder,
+			FlagsReverseOrder |
+			FlagsGridAll,
+
+		Style: StyleBar,
 	}
 }
 
@@ -100,6 +130,55 @@ func (w *Widget) SetFormatCallback(cb FormatCallback) *Widget {
 	return w
 }
 
+// Replaces plotted data with multiple series
+func (w *Widget) SetSeries(series ...WidgetSeries) *Widget {
+	w.series = series
+	return w
+}
+
+func (w *Widget) SetMode(mode Mode) *Widget {
+	w.Mode = mode
+	return w
+}
+
+func (w *Widget) SetStyle(style Style) *Widget {
+	w.Style = style
+	return w
+}
+
+func (w *Widget) SetGridStepCallback(cb StepCallback) *Widget {
+	w.GridStepCallback = cb
+	return w
+}
+
+func (w *Widget) SetSampleInterval(interval time.Duration) *Widget {
+	w.sampleInterval = interval
+	return w
+}
+
+// Sets sample interval unless it's already set, i.e. by data source
+func (w *Widget) SetDefaultSampleInterval(interval time.Duration) *Widget {
+	if w.sampleInterval <= 0 {
+		w.sampleInterval = interval
+	}
+	return w
+}
+
+func (w *Widget) SetStale(stale bool) *Widget {
+	w.Stale = stale
+	return w
+}
+
+func (w *Widget) SetError(err string) *Widget {
+	w.Error = err
+	return w
+}
+
+func (w *Widget) SetTimeToFull(seconds float64) *Widget {
+	w.TimeToFull = seconds
+	return w
+}
+
 func (w *Widget) SetAutoHeightPadding(ratio float64) *Widget {
 	w.autoMinMaxPadding = ratio
 	return w
@@ -107,14 +186,21 @@ func (w *Widget) SetAutoHeightPadding(ratio float64) *Widget {
 
 // TODO: remove GetData()?
 func (w *Widget) GetData() WidgetData {
-	return w.data
+	if len(w.series) == 0 {
+		return nil
+	}
+	return w.series[0].Data
+}
+
+func (w *Widget) GetSeries() []WidgetSeries {
+	return w.series
 }
 
 func (w *Widget) GetSanitizedMinMax() (xMin, xMax float64) {
 	xMin, xMax = w.xMin, w.xMax
 
 	if bitflags.Has(w.Flags, FlagsAutoMinMax) {
-		xMin, xMax = utils.SliceMinMax(w.data)
+		xMin, xMax = w.getDataMinMax()
 
 		if xMin < 0.0 {
 			xMin *= 1 + w.autoMinMaxPadding
@@ -167,12 +253,70 @@ func (w *Widget) GetWidgetRect() image.Rectangle {
 	return image.Rect(0, 0, w.Width, w.Height)
 }
 
-func (w *Widget) GetPlotMidLine() image.Rectangle {
+// Returns midline position and value range covered by one pixel
+func (w *Widget) getScale() (midPoint int, fracSize float64) {
 	xMin, xMax := w.GetSanitizedMinMax()
 	barRange := xMax - xMin
-	fracSize := barRange / float64(w.Height-1)
-	midPoint := float64(w.Height-1) - (barRange-xMax)/fracSize
-	midPointInt := int(math.Round(midPoint))
+	fracSize = barRange / float64(w.Height-1)
+	midPointF := float64(w.Height-1) - (barRange-xMax)/fracSize
+	return int(math.Round(midPointF)), fracSize
+}
+
+// TODO: make sure that flag FlagsReverseOrder handled everywhere where value accessed
+func (w *Widget) getValue(idx, x int) float64 {
+	return w.getDataValue(w.series[idx].Data, x)
+}
+
+// Returns value bounds of series idx at position x with mode applied. Bar
+// or area of the value spans from base to top
+func (w *Widget) getValueSpan(idx, x int) (top, base float64, valid bool) {
+	val := w.getValue(idx, x)
+	if !isFinite(val) {
+		return 0, 0, false
+	}
+
+	switch w.Mode {
+	case ModeStacked:
+		for prev := range idx {
+			if prevVal := w.getValue(prev, x); isFinite(prevVal) {
+				base += prevVal
+			}
+		}
+		return base + val, base, true
+	case ModeMirrored:
+		if idx > 0 {
+			return -val, 0, true
+		}
+		return val, 0, true
+	case ModeOverlay:
+	}
+	return val, 0, true
+}
+
+func (w *Widget) getDataMinMax() (xMin, xMax float64) {
+	xMin, xMax = math.Inf(1), math.Inf(-1)
+	for idx := range w.series {
+		for x := range w.series[idx].Data {
+			top, _, valid := w.getValueSpan(idx, x)
+			if !valid {
+				continue
+			}
+			xMin = min(xMin, top)
+			xMax = max(xMax, top)
+		}
+	}
+	if xMin > xMax {
+		return 0, 0
+	}
+	if w.Mode == ModeMirrored {
+		limit := max(math.Abs(xMin), math.Abs(xMax))
+		return -limit, limit
+	}
+	return xMin, xMax
+}
+
+func (w *Widget) GetPlotMidLine() image.Rectangle {
+	midPointInt, _ := w.getScale()
 
 	midline := image.Rectangle{
 		Min: image.Pt(0, midPointInt),
@@ -189,24 +333,15 @@ func (w *Widget) GetPlotMidLine() image.Rectangle {
 
 // TODO: Handle Inf/NaN xMin/xMax
 // TODO: widget auto size
-func (w *Widget) GetValueRect(x int) (rect image.Rectangle) {
-	var val float64
-	// TODO: make sure that flag FlagsReverseOrder handled everywhere where value accessed
-	if bitflags.Has(w.Flags, FlagsReverseOrder) {
-		val = w.data[len(w.data)-1-x]
-	} else {
-		val = w.data[x]
-	}
-	if math.IsNaN(val) || math.IsInf(val, 0) {
+func (w *Widget) GetValueRect(idx, x int) (rect image.Rectangle) {
+	top, base, valid := w.getValueSpan(idx, x)
+	if !valid {
 		return rect
 	}
-	xMin, xMax := w.GetSanitizedMinMax()
-	barRange := xMax - xMin
-	fracSize := barRange / float64(w.Height-1)
-	midPoint := float64(w.Height-1) - (barRange-xMax)/fracSize
-	midPointInt := int(math.Round(midPoint))
+	midPointInt, fracSize := w.getScale()
 
-	barHeight := int(math.Round(val / fracSize))
+	barHeight := int(math.Round(top / fracSize))
+	baseHeight := int(math.Round(base / fracSize))
 	// TODO: Flag for zero value bar appearance? Also draw it only on midline
 	// if barHeight == 0 {
 	// 	barHeight = 1
@@ -215,12 +350,12 @@ func (w *Widget) GetValueRect(x int) (rect image.Rectangle) {
 	pOffset := x * (w.barWidth + w.barSpacing)
 	midPointOffset := 0
 	// TODO: Flag for drawing on top of midline?
-	if barHeight < 0.0 {
+	if barHeight < baseHeight {
 		midPointOffset = 1
 	}
 	rect.Min.X = pOffset
 	rect.Max.X = pOffset + w.barWidth
-	rect.Min.Y = midPointInt + midPointOffset
+	rect.Min.Y = midPointInt - baseHeight + midPointOffset
 	rect.Max.Y = midPointInt - barHeight + midPointOffset
 
 	if !bitflags.Has(w.Flags, FlagsDebugIgnoreCanvasBounds) {
@@ -229,3 +364,7 @@ func (w *Widget) GetValueRect(x int) (rect image.Rectangle) {
 
 	return rect
 }
+
+func isFinite(val float64) bool {
+	return !math.IsNaN(val) && !math.IsInf(val, 0)
+}
@@ -0,0 +1,54 @@
+package plot
+
+import (
+	"fmt"
+	"slices"
+)
+
+type Style int
+
+const (
+	StyleBar Style = iota
+	StyleLine
+	StyleArea
+	StyleStep
+	StyleGradientArea
+)
+
+var styleNames = []string{
+	StyleBar:          "bar",
+	StyleLine:         "line",
+	StyleArea:         "area",
+	StyleStep:         "step",
+	StyleGradientArea: "gradient_area",
+}
+
+func (s Style) String() string {
+	if s < 0 || int(s) >= len(styleNames) {
+		return fmt.Sprintf("Style(%d)", int(s))
+	}
+	return styleNames[s]
+}
+
+// Returns the style following s, wrapping around after the last one
+func (s Style) Next() Style {
+	return (s + 1) % Style(len(styleNames))
+}
+
+func ParseStyle(name string) (Style, error) {
+	idx := slices.Index(styleNames, name)
+	if idx < 0 {
+		return StyleBar, fmt.Errorf("unknown plot style: %q", name)
+	}
+	return Style(idx), nil
+}
+
+// Style draws a line over values
+func (s Style) HasLine() bool {
+	return s != StyleBar
+}
+
+// Style fills the area between values and the midline
+func (s Style) HasFill() bool {
+	return s == StyleArea || s == StyleGradientArea
+}
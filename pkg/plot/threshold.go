@@ -0,0 +1,115 @@
+package plot
+
+import (
+	"image"
+	"math"
+	"slices"
+
+	"n4/gui-test/pkg/bitflags"
+)
+
+type ThresholdLevel int
+
+const (
+	ThresholdWarning ThresholdLevel = iota
+	ThresholdCritical
+)
+
+// Value marked with a horizontal line. Plot exceeds it when the latest value
+// is above it, or below it if Below is set, e.g. for free space
+type Threshold struct {
+	Value float64
+	Level ThresholdLevel
+	Below bool
+	// Value is seconds compared with time to full of widget instead of
+	// plotted values, i.e. warn when disk is full in less than 30 minutes.
+	// No line is drawn
+	TimeToFull bool
+}
+
+func (t Threshold) isExceededBy(val float64) bool {
+	if t.Below {
+		return val < t.Value
+	}
+	return val > t.Value
+}
+
+func (w *Widget) SetThresholds(thresholds ...Threshold) *Widget {
+	w.thresholds = thresholds
+	return w
+}
+
+func (w *Widget) GetThresholds() []Threshold {
+	return w.thresholds
+}
+
+// Returns top of the latest values of all series, stacked ones are summed
+func (w *Widget) getLatestTop() (top float64, valid bool) {
+	dataLen := len(w.GetData())
+	if dataLen == 0 {
+		return 0, false
+	}
+	x := 0
+	if bitflags.Has(w.Flags, FlagsReverseOrder) {
+		x = dataLen - 1
+	}
+	top = math.Inf(-1)
+	for idx := range w.series {
+		if len(w.series[idx].Data) != dataLen {
+			continue
+		}
+		if spanTop, _, spanValid := w.getValueSpan(idx, x); spanValid {
+			top, valid = max(top, spanTop), true
+		}
+	}
+	return top, valid
+}
+
+// Returns the highest level of thresholds exceeded by the latest value
+func (w *Widget) GetExceededLevel() (level ThresholdLevel, exceeded bool) {
+	top, valid := w.getLatestTop()
+	for _, threshold := range w.thresholds {
+		if threshold.TimeToFull {
+			// NOTE: NaN is never exceeded
+			if !threshold.isExceededBy(w.TimeToFull) {
+				continue
+			}
+		} else if !valid || !threshold.isExceededBy(top) {
+			continue
+		}
+		if !exceeded || threshold.Level > level {
+			level, exceeded = threshold.Level, true
+		}
+	}
+	return level, exceeded
+}
+
+// Horizontal line of a threshold in widget coordinates
+type ThresholdLine struct {
+	Rect  image.Rectangle
+	Level ThresholdLevel
+}
+
+// Returns lines of thresholds inside plot range, lower levels first so
+// higher ones are drawn on top
+func (w *Widget) GetThresholdLines() (lines []ThresholdLine) {
+	midPoint, fracSize := w.getScale()
+	thresholds := slices.Clone(w.thresholds)
+	slices.SortStableFunc(thresholds, func(a, b Threshold) int {
+		return int(a.Level - b.Level)
+	})
+	for _, threshold := range thresholds {
+		if threshold.TimeToFull {
+			continue
+		}
+		y := midPoint - int(math.Round(threshold.Value/fracSize))
+		if y < 0 || y >= w.Height {
+			continue
+		}
+		lines = append(lines, ThresholdLine{
+			Rect:  image.Rect(0, y, w.Width, y+1),
+			Level: threshold.Level,
+		})
+	}
+	return lines
+}
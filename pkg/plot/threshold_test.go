@@ -0,0 +1,92 @@
+package plot
+
+import (
+	"image"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWidget_GetExceededLevel(t *testing.T) {
+	thresholds := []Threshold{
+		{Value: 50, Level: ThresholdWarning},
+		{Value: 80, Level: ThresholdCritical},
+	}
+	tests := []struct {
+		name         string
+		data         WidgetData
+		thresholds   []Threshold
+		wantLevel    ThresholdLevel
+		wantExceeded bool
+	}{
+		{"below all", WidgetData{10, 90}, thresholds, 0, false},
+		{"warning", WidgetData{60, 10}, thresholds, ThresholdWarning, true},
+		{"critical", WidgetData{90, 10}, thresholds, ThresholdCritical, true},
+		{"equal is not exceeded", WidgetData{50}, thresholds, 0, false},
+		{"gap", WidgetData{math.NaN(), 90}, thresholds, 0, false},
+		{
+			"below", WidgetData{5},
+			[]Threshold{{Value: 10, Level: ThresholdWarning, Below: true}},
+			ThresholdWarning, true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// NOTE: The latest value is the first one
+			w := NewWidget("test", tt.data).SetThresholds(tt.thresholds...)
+			level, exceeded := w.GetExceededLevel()
+			assert.Equal(t, tt.wantExceeded, exceeded)
+			assert.Equal(t, tt.wantLevel, level)
+		})
+	}
+
+	t.Run("stacked sum", func(t *testing.T) {
+		w := NewWidget("test", nil).
+			SetMode(ModeStacked).
+			SetSeries(
+				WidgetSeries{Label: "a", Data: WidgetData{30}},
+				WidgetSeries{Label: "b", Data: WidgetData{30}},
+			).
+			SetThresholds(thresholds...)
+		level, exceeded := w.GetExceededLevel()
+		assert.True(t, exceeded)
+		assert.Equal(t, ThresholdWarning, level)
+	})
+
+	t.Run("time to full", func(t *testing.T) {
+		w := NewWidget("test", WidgetData{math.NaN()}).SetThresholds(
+			Threshold{Value: 1800, Level: ThresholdWarning, Below: true, TimeToFull: true},
+			Threshold{Value: 300, Level: ThresholdCritical, Below: true, TimeToFull: true},
+		)
+		_, exceeded := w.GetExceededLevel()
+		assert.False(t, exceeded, "unknown")
+
+		w.SetTimeToFull(math.Inf(1))
+		_, exceeded = w.GetExceededLevel()
+		assert.False(t, exceeded, "not filling")
+
+		w.SetTimeToFull(600)
+		level, exceeded := w.GetExceededLevel()
+		assert.True(t, exceeded)
+		assert.Equal(t, ThresholdWarning, level)
+	})
+}
+
+func TestWidget_GetThresholdLines(t *testing.T) {
+	w := NewWidget("test", WidgetData{0}).
+		SetSize(10, 101).
+		SetLimits(0, 100).
+		SetFlags(FlagsNone, true).
+		SetThresholds(
+			Threshold{Value: 90, Level: ThresholdCritical},
+			Threshold{Value: 50, Level: ThresholdWarning},
+			Threshold{Value: 200, Level: ThresholdCritical},
+			Threshold{Value: 30, Level: ThresholdCritical, TimeToFull: true},
+		)
+
+	assert.Equal(t, []ThresholdLine{
+		{Rect: image.Rect(0, 50, 10, 51), Level: ThresholdWarning},
+		{Rect: image.Rect(0, 10, 10, 11), Level: ThresholdCritical},
+	}, w.GetThresholdLines())
+}
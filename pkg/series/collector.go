@@ -1,13 +1,44 @@
 package series
 
+import (
+	"context"
+	"time"
+)
+
+// Collector of entries registered in app. Errors of Collect are reported by
+// stats update
 type ICollector interface {
-	Collect()
+	Collect() error
+}
+
+// Collector able to give up when context is done, i.e. on a hung network
+// mount. Stats prefer it over Collect
+type IContextCollector interface {
+	ICollector
+	CollectContext(ctx context.Context) error
+}
+
+// Error of collector that still collected the rest of its stats, i.e. one
+// unreadable disk out of several
+type PartialError struct {
+	Err error
+}
+
+func (e *PartialError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PartialError) Unwrap() error {
+	return e.Err
 }
 
+// Embedded by collectors, keeps size of their entries
 type Collector struct {
 	size int `validate:"gte=0"`
+	// Sample interval set by SetEntriesInterval, for entries created later
+	interval time.Duration
 }
 
-func (c *Collector) Collect() error {
-	panic("not implemented")
+func (c *Collector) setInterval(interval time.Duration) {
+	c.interval = interval
 }
@@ -0,0 +1,74 @@
+package series
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+type counterSample struct {
+	val float64
+	at  time.Time
+}
+
+// Turns cumulative counters, i.e. CPU times or transferred bytes, into per
+// second rates. Keeps the last raw value and time per counter, so rate is NaN
+// on the first sample, after counter reset and after entry was inactive.
+// Safe to use concurrently, i.e. reset while collector runs
+type Counters struct {
+	lock sync.Mutex
+	// Keyed by entry of the rate or any other counter identity
+	last map[any]counterSample
+}
+
+func NewCounters() *Counters {
+	return &Counters{last: make(map[any]counterSample)}
+}
+
+// Returns per second rate of counter key multiplied by unit and keeps val as
+// its last raw value. NaN val is a missing sample, the last raw value is
+// kept, so rate is calculated over the gap
+func (c *Counters) Rate(key any, val float64, at time.Time, unit float64) float64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.rate(key, val, at, unit)
+}
+
+// NOTE: Must be called with lock held
+func (c *Counters) rate(key any, val float64, at time.Time, unit float64) float64 {
+	if math.IsNaN(val) {
+		return math.NaN()
+	}
+	prev, present := c.last[key]
+	c.last[key] = counterSample{val, at}
+	elapsed := at.Sub(prev.at)
+	if !present || val < prev.val || elapsed <= 0 {
+		return math.NaN()
+	}
+	return (val - prev.val) * unit / elapsed.Seconds()
+}
+
+// Adds per second rates of counters multiplied by unit to active entries.
+// Raw values of inactive entries are forgotten, so the rate is not averaged
+// over the time nobody watched
+func (c *Counters) MapRates(valToEntryMapping []valToEntry, at time.Time, unit float64) {
+	rates := make([]valToEntry, 0, len(valToEntryMapping))
+	c.lock.Lock()
+	for _, mapping := range valToEntryMapping {
+		if !mapping.entry.IsActive() {
+			delete(c.last, mapping.entry)
+			continue
+		}
+		rate := c.rate(mapping.entry, mapping.val, at, unit)
+		rates = append(rates, valToEntry{rate, mapping.entry})
+	}
+	c.lock.Unlock()
+	MapValues(rates)
+}
+
+// Forgets all raw values, i.e. when counters start over for another process
+func (c *Counters) Reset() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	clear(c.last)
+}
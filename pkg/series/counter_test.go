@@ -0,0 +1,110 @@
+package series
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCounters_Rate(t *testing.T) {
+	start := time.Unix(0, 0)
+	tests := []struct {
+		name string
+		// Raw counter values, collected at the given seconds
+		values  []float64
+		seconds []float64
+		unit    float64
+		want    []float64
+	}{
+		{
+			"First sample is suppressed",
+			[]float64{10, 20, 40},
+			[]float64{0, 1, 2},
+			1,
+			[]float64{math.NaN(), 10, 20},
+		},
+		{
+			"Rate is per second",
+			[]float64{0, 10, 40},
+			[]float64{0, 2, 5},
+			1,
+			[]float64{math.NaN(), 5, 10},
+		},
+		{
+			"Unit",
+			[]float64{1, 1.5},
+			[]float64{0, 1},
+			100,
+			[]float64{math.NaN(), 50},
+		},
+		{
+			"Reset",
+			[]float64{100, 150, 20, 30},
+			[]float64{0, 1, 2, 3},
+			1,
+			[]float64{math.NaN(), 50, math.NaN(), 10},
+		},
+		{
+			"Missing sample",
+			[]float64{10, math.NaN(), 30},
+			[]float64{0, 1, 2},
+			1,
+			[]float64{math.NaN(), math.NaN(), 10},
+		},
+		{
+			"Same time",
+			[]float64{10, 20},
+			[]float64{1, 1},
+			1,
+			[]float64{math.NaN(), math.NaN()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCounters()
+			entry := NewEntry(4)
+			for x, val := range tt.values {
+				at := start.Add(time.Duration(tt.seconds[x] * float64(time.Second)))
+				got := c.Rate(entry, val, at, tt.unit)
+				if math.IsNaN(tt.want[x]) {
+					assert.True(t, math.IsNaN(got), "sample %d: %v", x, got)
+					continue
+				}
+				assert.InDelta(t, tt.want[x], got, 1e-9, "sample %d", x)
+			}
+		})
+	}
+}
+
+func TestCounters_MapRates(t *testing.T) {
+	c := NewCounters()
+	entry := NewEntry(4)
+	start := time.Unix(0, 0)
+	sub := &Subscriber{}
+
+	// Inactive entry is not collected
+	c.MapRates([]valToEntry{{10, entry}}, start, 1)
+	assert.Empty(t, c.last)
+
+	entry.Subscribe(sub)
+	c.MapRates([]valToEntry{{20, entry}}, start.Add(time.Second), 1)
+	assert.True(t, math.IsNaN(entry.GetLatestValue()))
+	c.MapRates([]valToEntry{{30, entry}}, start.Add(2*time.Second), 1)
+	assert.Equal(t, 10.0, entry.GetLatestValue())
+
+	// Rate after inactivity starts over
+	entry.Unsubscribe(sub)
+	c.MapRates([]valToEntry{{40, entry}}, start.Add(3*time.Second), 1)
+	entry.Subscribe(sub)
+	c.MapRates([]valToEntry{{100, entry}}, start.Add(4*time.Second), 1)
+	assert.True(t, math.IsNaN(entry.GetLatestValue()))
+	c.MapRates([]valToEntry{{102, entry}}, start.Add(5*time.Second), 1)
+	assert.Equal(t, 2.0, entry.GetLatestValue())
+
+	// Reset forgets raw values
+	c.Reset()
+	c.MapRates([]valToEntry{{200, entry}}, start.Add(6*time.Second), 1)
+	assert.True(t, math.IsNaN(entry.GetLatestValue()))
+}
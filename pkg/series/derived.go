@@ -0,0 +1,111 @@
+package series
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Returns entry by name, false if there is no such entry
+type EntryResolver func(name string) (*Entry, bool)
+
+// Collects value of expression over other entries. Inputs are subscribed
+// while the value is active, so they are not collected for nothing
+type DerivedCollector struct {
+	Collector
+
+	Name string
+
+	expr    *Expr
+	resolve EntryResolver
+
+	sub *Subscriber
+	// Subscribed inputs by name, nil while value is inactive
+	inputs map[string]*Entry
+
+	Value Entry `json:"value"`
+}
+
+func NewDerivedCollector(
+	name string, expr *Expr, resolve EntryResolver, size int,
+) *DerivedCollector {
+	if size < 1 {
+		panic("size must be greater than zero")
+	}
+	collector := DerivedCollector{
+		Collector: Collector{size: size},
+
+		Name: name,
+
+		expr:    expr,
+		resolve: resolve,
+
+		sub: &Subscriber{},
+
+		Value: *NewGapEntry(size),
+	}
+	return &collector
+}
+
+func (c *DerivedCollector) GetExpr() *Expr {
+	return c.expr
+}
+
+// Resolves inputs of expression and subscribes to them. NOTE: Inputs are
+// resolved on each collection, they may be missing for a while, i.e. disk
+// that is not mounted yet, or replaced, i.e. disk mounted again
+func (c *DerivedCollector) subscribe() error {
+	inputs := make(map[string]*Entry)
+	for _, name := range c.expr.Inputs() {
+		entry, present := c.resolve(name)
+		if !present {
+			c.release()
+			return fmt.Errorf("failed to evaluate %s: unknown entry %q", c.Name, name)
+		}
+		inputs[name] = entry
+	}
+
+	changed := false
+	for name, entry := range inputs {
+		if c.inputs[name] != entry {
+			entry.Subscribe(c.sub)
+			changed = true
+		}
+	}
+	for name, entry := range c.inputs {
+		if inputs[name] != entry {
+			entry.Unsubscribe(c.sub)
+		}
+	}
+	c.inputs = inputs
+	if changed {
+		c.expr.bind(inputs)
+	}
+	return nil
+}
+
+// Unsubscribes from inputs, so they stop being collected unless used
+// elsewhere
+func (c *DerivedCollector) release() {
+	for _, entry := range c.inputs {
+		entry.Unsubscribe(c.sub)
+	}
+	c.inputs = nil
+	c.expr.bind(nil)
+}
+
+func (c *DerivedCollector) Collect() error {
+	if !HasActiveEntries(c) {
+		c.release()
+		return nil
+	}
+
+	if err := c.subscribe(); err != nil {
+		c.Value.AddValue(math.NaN())
+		return err
+	}
+
+	c.Value.AddValue(c.expr.Eval(time.Now()))
+
+	return nil
+}
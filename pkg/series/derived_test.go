@@ -0,0 +1,70 @@
+package series
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDerivedCollector_Collect(t *testing.T) {
+	used, total := NewEntry(2), NewEntry(2)
+	entries := map[string]*Entry{"used": used}
+	resolve := func(name string) (*Entry, bool) {
+		entry, present := entries[name]
+		return entry, present
+	}
+
+	expr, err := ParseExpr("used / total * 100")
+	require.NoError(t, err)
+	c := NewDerivedCollector("used_percent", expr, resolve, 2)
+
+	// Inputs are not subscribed while value is inactive
+	require.NoError(t, c.Collect())
+	assert.False(t, used.IsActive())
+
+	sub := &Subscriber{}
+	c.Value.Subscribe(sub)
+
+	// Missing input is an error until it appears
+	require.ErrorContains(t, c.Collect(), `unknown entry "total"`)
+	assert.False(t, used.IsActive())
+	assert.True(t, math.IsNaN(c.Value.GetLatestValue()))
+
+	entries["total"] = total
+	require.NoError(t, c.Collect())
+	assert.True(t, used.IsActive())
+	assert.True(t, total.IsActive())
+	// Inputs got no values yet
+	assert.True(t, math.IsNaN(c.Value.GetLatestValue()))
+
+	used.AddValue(2)
+	total.AddValue(8)
+	require.NoError(t, c.Collect())
+	assert.Equal(t, 25.0, c.Value.GetLatestValue())
+
+	// Replaced input, i.e. disk mounted again, is resolved on collection
+	newTotal := NewEntry(2)
+	entries["total"] = newTotal
+	require.NoError(t, c.Collect())
+	assert.False(t, total.IsActive())
+	assert.True(t, newTotal.IsActive())
+	newTotal.AddValue(4)
+	require.NoError(t, c.Collect())
+	assert.Equal(t, 50.0, c.Value.GetLatestValue())
+
+	// Input that is gone releases the rest
+	delete(entries, "total")
+	require.ErrorContains(t, c.Collect(), `unknown entry "total"`)
+	assert.False(t, used.IsActive())
+	assert.False(t, newTotal.IsActive())
+	assert.True(t, math.IsNaN(c.Value.GetLatestValue()))
+
+	entries["total"] = newTotal
+	require.NoError(t, c.Collect())
+	c.Value.Unsubscribe(sub)
+	require.NoError(t, c.Collect())
+	assert.False(t, used.IsActive())
+	assert.False(t, newTotal.IsActive())
+}
@@ -1,7 +1,18 @@
 package series
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/shirou/gopsutil/v4/disk"
 )
@@ -10,6 +21,15 @@ type DiskStats struct {
 	Name string
 
 	mountpoint string
+	// Partition found by the latest discovery
+	partition disk.PartitionStat
+	// Unix nanoseconds of the time disk was found unmounted, zero while it's
+	// mounted. NOTE: Atomic, graphs check it while collector discovers
+	disconnected atomic.Int64
+	// NOTE: Result of usage call left running after timeout, hung mounts are
+	// not stat'ed again until it returns
+	pending chan diskUsageResult
+	trend   *TrendEstimator
 
 	Total       Entry `json:"total"`
 	Free        Entry `json:"free"`
@@ -20,6 +40,10 @@ type DiskStats struct {
 	InodesUsed        Entry `json:"inodes_used"`
 	InodesFree        Entry `json:"inodes_free"`
 	InodesUsedPercent Entry `json:"inodes_used_percent"`
+
+	// Seconds until disk is full at the trend of free space, +Inf if free
+	// space doesn't fall
+	TimeToFull Entry `json:"time_to_full"`
 }
 
 func NewDiskStats(size int, mountpoint string) *DiskStats {
@@ -40,42 +64,204 @@ func NewDiskStats(size int, mountpoint string) *DiskStats {
 		InodesUsed:        *NewEntry(size),
 		InodesFree:        *NewEntry(size),
 		InodesUsedPercent: *NewEntry(size),
+
+		TimeToFull: *NewGapEntry(size),
+		trend:      NewTrendEstimator(DefaultTrendWindow),
 	}
 	return ret
 }
 
+// Returns false if disk was unmounted, its entries get gaps until it's
+// mounted again or released
+func (d *DiskStats) IsConnected() bool {
+	return d.disconnected.Load() == 0
+}
+
+type diskUsageResult struct {
+	usage *disk.UsageStat
+	err   error
+}
+
+// Returns usage of disk or context error if it's not known in time
+func (d *DiskStats) usage(ctx context.Context) (*disk.UsageStat, error) {
+	if d.pending == nil {
+		d.pending = make(chan diskUsageResult, 1)
+		go func(result chan<- diskUsageResult) {
+			// NOTE: statfs is not interruptible, so context is not passed
+			usage, err := disk.Usage(d.mountpoint)
+			result <- diskUsageResult{usage, err}
+		}(d.pending)
+	}
+
+	select {
+	case result := <-d.pending:
+		d.pending = nil
+		return result.usage, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Returns partitions to collect usage of, injectable to test discovery
+type PartitionLister func() ([]disk.PartitionStat, error)
+
+// Returns all mounted partitions
+func ListPartitions() ([]disk.PartitionStat, error) {
+	return disk.Partitions(true)
+}
+
+// Partitions matching any of rules
+type DiskRules struct {
+	// Glob patterns, see filepath.Match
+	Mountpoints []string
+	Fstypes     []string
+	Devices     []*regexp.Regexp
+}
+
+func (r DiskRules) IsEmpty() bool {
+	return len(r.Mountpoints) == 0 && len(r.Fstypes) == 0 && len(r.Devices) == 0
+}
+
+func (r DiskRules) Match(part disk.PartitionStat) bool {
+	for _, pattern := range r.Mountpoints {
+		// NOTE: Patterns are validated when rules are created
+		if matched, _ := filepath.Match(pattern, part.Mountpoint); matched {
+			return true
+		}
+	}
+	if slices.Contains(r.Fstypes, part.Fstype) {
+		return true
+	}
+	return slices.ContainsFunc(r.Devices, func(re *regexp.Regexp) bool {
+		return re.MatchString(part.Device)
+	})
+}
+
+// Selects partitions to collect. Partition is collected if it matches
+// Include, when it's not empty, and doesn't match Exclude
+type DiskFilter struct {
+	Include DiskRules
+	Exclude DiskRules
+}
+
+func (f DiskFilter) Allows(part disk.PartitionStat) bool {
+	if !f.Include.IsEmpty() && !f.Include.Match(part) {
+		return false
+	}
+	return !f.Exclude.Match(part)
+}
+
+const (
+	// Time between discoveries of mounted and unmounted disks
+	DiskDiscoverInterval = 10 * time.Second
+	// Disconnected disks are kept for a while in case they are mounted again
+	DefaultDiskGracePeriod = time.Minute
+)
+
+var _ IContextCollector = (*DiskCollector)(nil)
+
+// Collects usage of disks found by periodic discovery. Disks gone are marked
+// disconnected and released after grace period
 type DiskCollector struct {
 	Collector
 
-	Disks map[string]*DiskStats
+	listPartitions PartitionLister
+	filter         DiskFilter
+	discovered     time.Time
+
+	GracePeriod time.Duration
+
+	// NOTE: Guards disks, graphs read them while collector discovers
+	lock  sync.Mutex
+	disks map[string]*DiskStats
 }
 
-func NewDiskCollector(size int) *DiskCollector {
+func NewDiskCollector(lister PartitionLister, size int) *DiskCollector {
 	if size < 1 {
 		panic("size must be greater than zero")
 	}
 	ret := &DiskCollector{
 		Collector: Collector{size: size},
 
-		Disks: map[string]*DiskStats{},
+		listPartitions: lister,
+
+		GracePeriod: DefaultDiskGracePeriod,
+
+		disks: map[string]*DiskStats{},
 	}
 
 	return ret
 }
 
-// TODO: handle new devices?
-// TODO: handle disconnected devices?
-// TODO: filter from config
+// Returns disks sorted by name, including disconnected ones still in grace
+// period
+func (c *DiskCollector) GetDisks() []*DiskStats {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return slices.SortedFunc(maps.Values(c.disks), func(a, b *DiskStats) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+}
+
+// Sets partitions to collect. Disks the filter doesn't allow are released at
+// once
+func (c *DiskCollector) SetFilter(filter DiskFilter) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.filter = filter
+	maps.DeleteFunc(c.disks, func(_ string, dStats *DiskStats) bool {
+		return !filter.Allows(dStats.partition)
+	})
+}
+
+// NOTE: Disks are walked under lock, discovery changes them while entries
+// are walked on timeout of collection
+func (c *DiskCollector) walkEntries(fn func(entry *Entry)) {
+	for _, dStats := range c.GetDisks() {
+		walkEntries(reflect.ValueOf(dStats), fn)
+	}
+}
+
+// Adds disks mounted since the last discovery and marks unmounted ones as
+// disconnected. Disks disconnected longer than grace period are released.
+// Partitions filter doesn't allow are skipped
 func (c *DiskCollector) Discover() error {
-	partitions, err := disk.Partitions(true)
+	partitions, err := c.listPartitions()
 	if err != nil {
 		return fmt.Errorf("failed to get disk stats: %w", err)
 	}
+	now := time.Now()
+	c.discovered = now
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
 
+	mounted := make(map[string]struct{}, len(partitions))
 	for _, part := range partitions {
-		_, present := c.Disks[part.Mountpoint]
+		if !c.filter.Allows(part) {
+			continue
+		}
+		mounted[part.Mountpoint] = struct{}{}
+		dStats, present := c.disks[part.Mountpoint]
 		if !present {
-			c.Disks[part.Mountpoint] = NewDiskStats(c.size, part.Mountpoint)
+			dStats = NewDiskStats(c.size, part.Mountpoint)
+			SetEntriesInterval(dStats, c.interval)
+			c.disks[part.Mountpoint] = dStats
+		}
+		dStats.partition = part
+		dStats.disconnected.Store(0)
+	}
+
+	for mountpoint, dStats := range c.disks {
+		if _, present := mounted[mountpoint]; present {
+			continue
+		}
+		disconnected := dStats.disconnected.Load()
+		if disconnected == 0 {
+			dStats.disconnected.Store(now.UnixNano())
+		} else if now.Sub(time.Unix(0, disconnected)) > c.GracePeriod {
+			delete(c.disks, mountpoint)
 		}
 	}
 
@@ -83,15 +269,47 @@ func (c *DiskCollector) Discover() error {
 }
 
 func (c *DiskCollector) Collect() error {
-	for _, dStats := range c.Disks {
+	return c.CollectContext(context.Background())
+}
+
+// Collects usage of disks until ctx is done, disks left are recorded as gaps.
+// Disks that can't be read are recorded as gaps too, others are collected
+// and PartialError is returned
+func (c *DiskCollector) CollectContext(ctx context.Context) error {
+	var discoverErr error
+	if time.Since(c.discovered) >= DiskDiscoverInterval {
+		discoverErr = c.Discover()
+	}
+
+	var ctxErr error
+	var diskErrs []error
+	collected := 0
+	for _, dStats := range c.GetDisks() {
 		if !HasActiveEntries(dStats) {
 			continue
 		}
+		if ctxErr != nil || !dStats.IsConnected() {
+			AddGaps(dStats)
+			continue
+		}
 
-		usage, err := disk.Usage(dStats.mountpoint)
+		usage, err := dStats.usage(ctx)
+		if ctx.Err() != nil {
+			ctxErr = fmt.Errorf(
+				"failed to get stats of disk %s: %w", dStats.Name, ctx.Err(),
+			)
+			AddGaps(dStats)
+			continue
+		}
 		if err != nil {
-			return fmt.Errorf("failed to get disk stats: %w", err)
+			diskErrs = append(diskErrs, fmt.Errorf(
+				"failed to get stats of disk %s: %w", dStats.Name, err,
+			))
+			AddGaps(dStats)
+			continue
 		}
+		collected++
+		dStats.trend.Add(time.Now(), float64(usage.Free))
 
 		MapValues([]valToEntry{
 			{float64(usage.Total), &dStats.Total},
@@ -103,8 +321,23 @@ func (c *DiskCollector) Collect() error {
 			{float64(usage.InodesUsed), &dStats.InodesUsed},
 			{float64(usage.InodesFree), &dStats.InodesFree},
 			{float64(usage.InodesUsedPercent), &dStats.InodesUsedPercent},
+
+			{dStats.trend.TimeToZero(), &dStats.TimeToFull},
 		})
 	}
 
-	return nil
+	if ctxErr != nil {
+		return ctxErr
+	}
+	if discoverErr != nil {
+		diskErrs = append(diskErrs, discoverErr)
+	}
+	if len(diskErrs) == 0 {
+		return nil
+	}
+	err := errors.Join(diskErrs...)
+	if collected > 0 {
+		return &PartialError{err}
+	}
+	return err
 }
@@ -0,0 +1,167 @@
+package series
+
+import (
+	"math"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/disk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func diskNames(disks []*DiskStats) []string {
+	names := make([]string, len(disks))
+	for x, d := range disks {
+		names[x] = d.Name
+	}
+	return names
+}
+
+func TestDiskCollector_Discover(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	mounted := []string{first}
+	lister := func() ([]disk.PartitionStat, error) {
+		parts := make([]disk.PartitionStat, len(mounted))
+		for x, mountpoint := range mounted {
+			parts[x] = disk.PartitionStat{Mountpoint: mountpoint}
+		}
+		return parts, nil
+	}
+
+	c := NewDiskCollector(lister, 2)
+	c.GracePeriod = 0
+	SetEntriesInterval(c, 10*time.Second)
+
+	require.NoError(t, c.Discover())
+	assert.Equal(t, []string{first}, diskNames(c.GetDisks()))
+
+	// Plugged in disk gets interval of the collector
+	mounted = append(mounted, second)
+	require.NoError(t, c.Discover())
+	disks := c.GetDisks()
+	require.Len(t, disks, 2)
+	assert.Equal(t, 10*time.Second, disks[1].Free.GetInterval())
+
+	sub := &Subscriber{}
+	disks[1].Free.Subscribe(sub)
+
+	// Unplugged disk is kept as disconnected and collected as gap
+	mounted = mounted[:1]
+	require.NoError(t, c.Discover())
+	require.Len(t, c.GetDisks(), 2)
+	assert.False(t, disks[1].IsConnected())
+	require.NoError(t, c.Collect())
+	assert.True(t, math.IsNaN(disks[1].Free.GetData().GetFirstValue()))
+
+	// Disk mounted again within grace period keeps its stats
+	mounted = append(mounted, second)
+	require.NoError(t, c.Discover())
+	assert.True(t, disks[1].IsConnected())
+	require.NoError(t, c.Collect())
+	assert.Greater(t, disks[1].Free.GetData().GetFirstValue(), 0.0)
+
+	// Disk is released after grace period
+	mounted = mounted[:1]
+	require.NoError(t, c.Discover())
+	require.NoError(t, c.Discover())
+	assert.Equal(t, []string{first}, diskNames(c.GetDisks()))
+}
+
+func TestDiskFilter_Allows(t *testing.T) {
+	exclude := DiskRules{
+		Mountpoints: []string{"/snap/*"},
+		Fstypes:     []string{"tmpfs"},
+		Devices:     []*regexp.Regexp{regexp.MustCompile("^/dev/loop")},
+	}
+	tests := []struct {
+		name   string
+		filter DiskFilter
+		part   disk.PartitionStat
+		want   bool
+	}{
+		{
+			name: "No rules",
+			part: disk.PartitionStat{Mountpoint: "/", Device: "/dev/sda1", Fstype: "ext4"},
+			want: true,
+		},
+		{
+			name:   "Not excluded",
+			filter: DiskFilter{Exclude: exclude},
+			part:   disk.PartitionStat{Mountpoint: "/", Device: "/dev/sda1", Fstype: "ext4"},
+			want:   true,
+		},
+		{
+			name:   "Excluded by mountpoint",
+			filter: DiskFilter{Exclude: exclude},
+			part:   disk.PartitionStat{Mountpoint: "/snap/core", Device: "/dev/sda1", Fstype: "ext4"},
+		},
+		{
+			name:   "Glob doesn't match nested mountpoint",
+			filter: DiskFilter{Exclude: exclude},
+			part:   disk.PartitionStat{Mountpoint: "/snap/core/1/x", Device: "/dev/sda1", Fstype: "ext4"},
+			want:   true,
+		},
+		{
+			name:   "Excluded by fstype",
+			filter: DiskFilter{Exclude: exclude},
+			part:   disk.PartitionStat{Mountpoint: "/run", Device: "tmpfs", Fstype: "tmpfs"},
+		},
+		{
+			name:   "Excluded by device",
+			filter: DiskFilter{Exclude: exclude},
+			part:   disk.PartitionStat{Mountpoint: "/mnt/img", Device: "/dev/loop3", Fstype: "ext4"},
+		},
+		{
+			name: "Not included",
+			filter: DiskFilter{
+				Include: DiskRules{Mountpoints: []string{"/", "/mnt/*"}},
+			},
+			part: disk.PartitionStat{Mountpoint: "/boot", Device: "/dev/sda2", Fstype: "vfat"},
+		},
+		{
+			name: "Included and excluded",
+			filter: DiskFilter{
+				Include: DiskRules{Mountpoints: []string{"/", "/mnt/*"}},
+				Exclude: exclude,
+			},
+			part: disk.PartitionStat{Mountpoint: "/mnt/img", Device: "/dev/loop3", Fstype: "ext4"},
+		},
+		{
+			name: "Included",
+			filter: DiskFilter{
+				Include: DiskRules{Mountpoints: []string{"/", "/mnt/*"}},
+				Exclude: exclude,
+			},
+			part: disk.PartitionStat{Mountpoint: "/mnt/data", Device: "/dev/sdb1", Fstype: "ext4"},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.Allows(tt.part))
+		})
+	}
+}
+
+func TestDiskCollector_SetFilter(t *testing.T) {
+	kept, hidden := t.TempDir(), t.TempDir()
+	lister := func() ([]disk.PartitionStat, error) {
+		return []disk.PartitionStat{
+			{Mountpoint: kept, Fstype: "ext4"},
+			{Mountpoint: hidden, Fstype: "tmpfs"},
+		}, nil
+	}
+
+	c := NewDiskCollector(lister, 2)
+	require.NoError(t, c.Discover())
+	require.Len(t, c.GetDisks(), 2)
+
+	// Disks already found are released
+	c.SetFilter(DiskFilter{Exclude: DiskRules{Fstypes: []string{"tmpfs"}}})
+	assert.Equal(t, []string{kept}, diskNames(c.GetDisks()))
+
+	require.NoError(t, c.Discover())
+	assert.Equal(t, []string{kept}, diskNames(c.GetDisks()))
+}
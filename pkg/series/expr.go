@@ -0,0 +1,416 @@
+package series
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Node of parsed expression. Nodes of functions over time keep their state,
+// so expression is evaluated once per collection
+type exprNode interface {
+	eval(at time.Time) float64
+}
+
+type numberNode float64
+
+func (n numberNode) eval(time.Time) float64 {
+	return float64(n)
+}
+
+// Entry referenced by name, resolved when derived entry gets active
+type entryNode struct {
+	name  string
+	entry *Entry
+}
+
+func (n *entryNode) eval(time.Time) float64 {
+	if n.entry == nil {
+		return math.NaN()
+	}
+	return n.entry.GetLatestValue()
+}
+
+type negNode struct {
+	arg exprNode
+}
+
+func (n *negNode) eval(at time.Time) float64 {
+	return -n.arg.eval(at)
+}
+
+type binaryNode struct {
+	op          byte
+	left, right exprNode
+}
+
+func (n *binaryNode) eval(at time.Time) float64 {
+	left, right := n.left.eval(at), n.right.eval(at)
+	switch n.op {
+	case '+':
+		return left + right
+	case '-':
+		return left - right
+	case '*':
+		return left * right
+	}
+	return left / right
+}
+
+// Increase of counter argument per second between evaluations. NaN when the
+// counter goes down, i.e. it wrapped or restarted
+type rateNode struct {
+	arg      exprNode
+	counters *Counters
+}
+
+func (n *rateNode) eval(at time.Time) float64 {
+	return n.counters.Rate(n, n.arg.eval(at), at, 1)
+}
+
+// Aggregate of argument values over window, gaps are skipped. NOTE: Value is
+// taken once per new sample of the entries in argument, so the window is
+// counted in their samples, not in evaluations of the expression
+type windowNode struct {
+	arg       exprNode
+	refs      []*entryNode
+	window    time.Duration
+	aggregate func(values []float64) float64
+
+	// Input samples already taken
+	samples uint64
+	times   []time.Time
+	values  []float64
+}
+
+// Returns samples of entries in argument and the longest of their intervals
+func (n *windowNode) inputSamples() (samples uint64, interval time.Duration) {
+	for _, ref := range n.refs {
+		if ref.entry == nil {
+			continue
+		}
+		samples += ref.entry.GetSamples()
+		interval = max(interval, ref.entry.GetInterval())
+	}
+	return samples, interval
+}
+
+func (n *windowNode) eval(at time.Time) float64 {
+	val := n.arg.eval(at)
+	samples, interval := n.inputSamples()
+	if len(n.refs) == 0 || samples != n.samples {
+		n.samples = samples
+		n.times = append(n.times, at)
+		n.values = append(n.values, val)
+	}
+
+	// NOTE: Window is counted in time while sample interval is unknown
+	old := 0
+	if interval > 0 {
+		old = max(len(n.values)-max(int(n.window/interval), 1), 0)
+	} else {
+		for old < len(n.times) && at.Sub(n.times[old]) >= n.window {
+			old++
+		}
+	}
+	n.times = slices.Delete(n.times, 0, old)
+	n.values = slices.Delete(n.values, 0, old)
+
+	values := make([]float64, 0, len(n.values))
+	for _, val := range n.values {
+		if !math.IsNaN(val) {
+			values = append(values, val)
+		}
+	}
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	return n.aggregate(values)
+}
+
+type clampNode struct {
+	arg, lo, hi exprNode
+}
+
+func (n *clampNode) eval(at time.Time) float64 {
+	return min(max(n.arg.eval(at), n.lo.eval(at)), n.hi.eval(at))
+}
+
+func avgOf(values []float64) float64 {
+	sum := 0.0
+	for _, val := range values {
+		sum += val
+	}
+	return sum / float64(len(values))
+}
+
+var windowFuncs = map[string]func(values []float64) float64{
+	"avg_over": avgOf,
+	"max_over": slices.Max[[]float64],
+	"min_over": slices.Min[[]float64],
+}
+
+// Arithmetic expression over entries, i.e. sys_mem_used / sys_mem_total * 100.
+// Supports + - * /, parentheses and functions: rate(x), avg_over(x, 30s),
+// max_over(x, 1m), min_over(x, 1m) and clamp(x, lo, hi)
+type Expr struct {
+	source string
+	root   exprNode
+	refs   []*entryNode
+}
+
+// Returns names of entries referenced by expression, without repeats
+func (e *Expr) Inputs() []string {
+	var names []string
+	for _, ref := range e.refs {
+		if !slices.Contains(names, ref.name) {
+			names = append(names, ref.name)
+		}
+	}
+	return names
+}
+
+func (e *Expr) String() string {
+	return e.source
+}
+
+// Evaluates expression at time at. Results that are not finite, i.e. of
+// division by zero, are NaN
+func (e *Expr) Eval(at time.Time) float64 {
+	val := e.root.eval(at)
+	if math.IsInf(val, 0) {
+		return math.NaN()
+	}
+	return val
+}
+
+// Sets entries of references, entries missing from inputs are unset
+func (e *Expr) bind(inputs map[string]*Entry) {
+	for _, ref := range e.refs {
+		ref.entry = inputs[ref.name]
+	}
+}
+
+func ParseExpr(source string) (*Expr, error) {
+	tokens, err := lexExpr(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", source, err)
+	}
+	p := &exprParser{tokens: tokens}
+	root, err := p.parseSum()
+	if err == nil && p.peek().kind != tokenEnd {
+		err = fmt.Errorf("unexpected %q", p.peek().text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", source, err)
+	}
+	return &Expr{source: source, root: root, refs: p.refs}, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenNumber
+	tokenDuration
+	tokenName
+	// Operators, parentheses and commas
+	tokenPunct
+)
+
+type exprToken struct {
+	kind tokenKind
+	text string
+}
+
+func isNameRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func lexExpr(source string) ([]exprToken, error) {
+	var tokens []exprToken
+	runes := []rune(source)
+	for x := 0; x < len(runes); {
+		r := runes[x]
+		switch {
+		case unicode.IsSpace(r):
+			x++
+		case strings.ContainsRune("+-*/(),", r):
+			tokens = append(tokens, exprToken{tokenPunct, string(r)})
+			x++
+		case unicode.IsDigit(r) || r == '.':
+			start := x
+			for x < len(runes) && (unicode.IsDigit(runes[x]) || runes[x] == '.') {
+				x++
+			}
+			kind := tokenNumber
+			// NOTE: Number followed by unit is duration, i.e. 30s
+			for x < len(runes) && unicode.IsLetter(runes[x]) {
+				kind = tokenDuration
+				x++
+			}
+			tokens = append(tokens, exprToken{kind, string(runes[start:x])})
+		case isNameRune(r):
+			start := x
+			for x < len(runes) && isNameRune(runes[x]) {
+				x++
+			}
+			tokens = append(tokens, exprToken{tokenName, string(runes[start:x])})
+		default:
+			return nil, fmt.Errorf("unexpected %q", r)
+		}
+	}
+	return append(tokens, exprToken{kind: tokenEnd}), nil
+}
+
+type exprParser struct {
+	tokens []exprToken
+	pos    int
+	refs   []*entryNode
+}
+
+func (p *exprParser) peek() exprToken {
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() exprToken {
+	token := p.tokens[p.pos]
+	if token.kind != tokenEnd {
+		p.pos++
+	}
+	return token
+}
+
+// Consumes punctuation token if it's the next one
+func (p *exprParser) accept(punct string) bool {
+	if token := p.peek(); token.kind == tokenPunct && token.text == punct {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *exprParser) expect(punct string) error {
+	if !p.accept(punct) {
+		return fmt.Errorf("expected %q", punct)
+	}
+	return nil
+}
+
+func (p *exprParser) parseSum() (exprNode, error) {
+	left, err := p.parseProduct()
+	for err == nil {
+		op := p.peek().text
+		if !p.accept("+") && !p.accept("-") {
+			break
+		}
+		var right exprNode
+		right, err = p.parseProduct()
+		left = &binaryNode{op[0], left, right}
+	}
+	return left, err
+}
+
+func (p *exprParser) parseProduct() (exprNode, error) {
+	left, err := p.parseUnary()
+	for err == nil {
+		op := p.peek().text
+		if !p.accept("*") && !p.accept("/") {
+			break
+		}
+		var right exprNode
+		right, err = p.parseUnary()
+		left = &binaryNode{op[0], left, right}
+	}
+	return left, err
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if p.accept("-") {
+		arg, err := p.parseUnary()
+		return &negNode{arg}, err
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	token := p.next()
+	switch token.kind {
+	case tokenNumber:
+		val, err := strconv.ParseFloat(token.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", token.text)
+		}
+		return numberNode(val), nil
+	case tokenName:
+		if p.accept("(") {
+			return p.parseCall(token.text)
+		}
+		ref := &entryNode{name: token.text}
+		p.refs = append(p.refs, ref)
+		return ref, nil
+	case tokenPunct:
+		if token.text == "(" {
+			node, err := p.parseSum()
+			if err != nil {
+				return nil, err
+			}
+			return node, p.expect(")")
+		}
+	case tokenEnd:
+		return nil, fmt.Errorf("unexpected end")
+	}
+	return nil, fmt.Errorf("unexpected %q", token.text)
+}
+
+// Parses arguments of function after opening parenthesis
+func (p *exprParser) parseCall(name string) (exprNode, error) {
+	firstRef := len(p.refs)
+	arg, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+
+	if aggregate, present := windowFuncs[name]; present {
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+		token := p.next()
+		window, err := time.ParseDuration(token.text)
+		if token.kind != tokenDuration || err != nil || window <= 0 {
+			return nil, fmt.Errorf("%s window must be a duration, i.e. 30s", name)
+		}
+		node := &windowNode{
+			arg:       arg,
+			refs:      slices.Clone(p.refs[firstRef:]),
+			window:    window,
+			aggregate: aggregate,
+		}
+		return node, p.expect(")")
+	}
+
+	switch name {
+	case "rate":
+		return &rateNode{arg: arg, counters: NewCounters()}, p.expect(")")
+	case "clamp":
+		node := &clampNode{arg: arg}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+		if node.lo, err = p.parseSum(); err != nil {
+			return nil, err
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+		if node.hi, err = p.parseSum(); err != nil {
+			return nil, err
+		}
+		return node, p.expect(")")
+	}
+	return nil, fmt.Errorf("unknown function %q", name)
+}
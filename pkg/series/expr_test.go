@@ -0,0 +1,167 @@
+package series
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Returns entries with values subscribed by a test subscriber
+func newTestEntries(t *testing.T, values map[string]float64) map[string]*Entry {
+	t.Helper()
+	entries := make(map[string]*Entry)
+	for name, val := range values {
+		entry := NewEntry(4)
+		entry.Subscribe(&Subscriber{})
+		entry.AddValue(val)
+		entries[name] = entry
+	}
+	return entries
+}
+
+func TestParseExpr(t *testing.T) {
+	entries := newTestEntries(t, map[string]float64{
+		"sys_mem_used":  2,
+		"sys_mem_total": 8,
+		"disk___free":   5,
+		"zero":          0,
+	})
+	tests := []struct {
+		source     string
+		want       float64
+		wantInputs []string
+	}{
+		{"1 + 2 * 3", 7, nil},
+		{"(1 + 2) * 3", 9, nil},
+		{"10 - 4 - 3", 3, nil},
+		{"12 / 3 / 2", 2, nil},
+		{"-2 * -3", 6, nil},
+		{"1.5 + .5", 2, nil},
+		{
+			"sys_mem_used / sys_mem_total * 100", 25,
+			[]string{"sys_mem_used", "sys_mem_total"},
+		},
+		{"disk___free - disk___free", 0, []string{"disk___free"}},
+		{"clamp(disk___free, 0, 3)", 3, []string{"disk___free"}},
+		{"clamp(-disk___free, 0, 3)", 0, []string{"disk___free"}},
+		{"sys_mem_used / zero", math.NaN(), []string{"sys_mem_used", "zero"}},
+		{"missing + 1", math.NaN(), []string{"missing"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			expr, err := ParseExpr(tt.source)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantInputs, expr.Inputs())
+
+			expr.bind(entries)
+			got := expr.Eval(time.Now())
+			if math.IsNaN(tt.want) {
+				assert.True(t, math.IsNaN(got), got)
+				return
+			}
+			assert.InDelta(t, tt.want, got, 1e-9)
+		})
+	}
+}
+
+func TestParseExpr_Error(t *testing.T) {
+	tests := []struct {
+		source  string
+		wantErr string
+	}{
+		{"", "unexpected end"},
+		{"1 +", "unexpected end"},
+		{"(1 + 2", `expected ")"`},
+		{"1 2", `unexpected "2"`},
+		{"a % b", `unexpected '%'`},
+		{"avg(a)", `unknown function "avg"`},
+		{"avg_over(a)", `expected ","`},
+		{"avg_over(a, 30)", "window must be a duration"},
+		{"max_over(a, 30x)", "window must be a duration"},
+		{"clamp(a, 0)", `expected ","`},
+		{"1..2", `invalid number "1..2"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			_, err := ParseExpr(tt.source)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestExpr_Eval_OverTime(t *testing.T) {
+	entry := NewEntry(4)
+	entry.Subscribe(&Subscriber{})
+	inputs := map[string]*Entry{"bytes": entry}
+	start := time.Unix(0, 0)
+
+	tests := []struct {
+		source string
+		// Input values added every second and expected results
+		values []float64
+		want   []float64
+	}{
+		{
+			// NOTE: Counter reset is a gap
+			"rate(bytes)",
+			[]float64{100, 150, math.NaN(), 250, 10, 30},
+			[]float64{math.NaN(), 50, math.NaN(), 50, math.NaN(), 20},
+		},
+		{
+			"avg_over(bytes, 2s)",
+			[]float64{1, 3, math.NaN(), 7},
+			[]float64{1, 2, 3, 7},
+		},
+		{
+			"max_over(bytes * 2, 3s)",
+			[]float64{5, 1, 2, 1},
+			[]float64{10, 10, 10, 4},
+		},
+		{
+			"min_over(bytes, 1m)",
+			[]float64{5, 1, 2, math.NaN()},
+			[]float64{5, 1, 1, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			expr, err := ParseExpr(tt.source)
+			require.NoError(t, err)
+			expr.bind(inputs)
+			for x, val := range tt.values {
+				entry.AddValue(val)
+				got := expr.Eval(start.Add(time.Duration(x) * time.Second))
+				if math.IsNaN(tt.want[x]) {
+					assert.True(t, math.IsNaN(got), "sample %d: %v", x, got)
+					continue
+				}
+				assert.InDelta(t, tt.want[x], got, 1e-9, "sample %d", x)
+			}
+		})
+	}
+}
+
+func TestExpr_Eval_OverSlowerInput(t *testing.T) {
+	entry := NewEntry(4)
+	entry.Subscribe(&Subscriber{})
+	entry.SetInterval(2 * time.Second)
+	expr, err := ParseExpr("avg_over(bytes, 4s)")
+	require.NoError(t, err)
+	expr.bind(map[string]*Entry{"bytes": entry})
+	start := time.Unix(0, 0)
+
+	// NOTE: Expression is evaluated every second, input gets a sample every
+	// other second, so the window holds its two latest samples
+	values := []float64{1, 5, 9}
+	want := []float64{1, 1, 3, 3, 7, 7}
+	for x := range want {
+		if x%2 == 0 {
+			entry.AddValue(values[x/2])
+		}
+		got := expr.Eval(start.Add(time.Duration(x) * time.Second))
+		assert.InDelta(t, want[x], got, 1e-9, "evaluation %d", x)
+	}
+}
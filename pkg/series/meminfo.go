@@ -0,0 +1,38 @@
+package series
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Parses data in /proc/meminfo format. Values in kB are converted to bytes,
+// values without unit like HugePages_Total are counts
+func parseMeminfo(r io.Reader) (map[string]uint64, error) {
+	values := make(map[string]uint64)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), ":")
+		if !found {
+			continue
+		}
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			continue
+		}
+		num, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse meminfo %s: %w", key, err)
+		}
+		if len(fields) > 1 && fields[1] == "kB" {
+			num *= 1024
+		}
+		values[key] = num
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read meminfo: %w", err)
+	}
+	return values, nil
+}
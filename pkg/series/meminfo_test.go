@@ -0,0 +1,40 @@
+package series
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMeminfo(t *testing.T) {
+	file, err := os.Open("testdata/meminfo")
+	require.NoError(t, err)
+	defer file.Close()
+
+	meminfo, err := parseMeminfo(file)
+	require.NoError(t, err)
+
+	tests := []struct {
+		key  string
+		want uint64
+	}{
+		{"MemTotal", 16318412 * 1024},
+		{"CommitLimit", 16547808 * 1024},
+		{"Committed_AS", 18226316 * 1024},
+		{"Dirty", 2616 * 1024},
+		{"HugePages_Total", 64},
+		{"HugePages_Free", 48},
+		{"Hugepagesize", 2048 * 1024},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			assert.Equal(t, tt.want, meminfo[tt.key])
+		})
+	}
+
+	_, err = parseMeminfo(strings.NewReader("MemTotal: lots kB\n"))
+	assert.ErrorContains(t, err, "MemTotal")
+}
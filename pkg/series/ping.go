@@ -0,0 +1,342 @@
+package series
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"runtime"
+	"time"
+
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+)
+
+const (
+	DefaultPingTimeout = time.Second
+
+	// Number of the last probes packet loss is calculated over
+	pingLossWindow = 20
+	// Smoothing factor of interarrival jitter from RFC 3550
+	pingJitterGain = 1.0 / 16
+)
+
+type PingProbe int
+
+const (
+	PingICMP PingProbe = iota
+	PingTCP
+	PingHTTP
+)
+
+var pingProbeNames = []string{
+	PingICMP: "icmp",
+	PingTCP:  "tcp",
+	PingHTTP: "http",
+}
+
+func (p PingProbe) String() string {
+	if p < 0 || int(p) >= len(pingProbeNames) {
+		return fmt.Sprintf("PingProbe(%d)", int(p))
+	}
+	return pingProbeNames[p]
+}
+
+// Returns probe by name used in config. NOTE: ICMP is rejected on Windows,
+// unprivileged ICMP sockets are not supported there and raw ones need
+// administrator rights
+func ParsePingProbe(name string) (PingProbe, error) {
+	for p, probeName := range pingProbeNames {
+		if probeName != name {
+			continue
+		}
+		if PingProbe(p) == PingICMP && runtime.GOOS == "windows" {
+			return 0, errors.New("icmp ping probe is not supported on windows, use tcp or http")
+		}
+		return PingProbe(p), nil
+	}
+	return 0, fmt.Errorf("unknown ping probe: %q", name)
+}
+
+// Probe failed before anything was sent, i.e. socket is not permitted. It's
+// an error of collector, not a lost probe
+type pingSetupError struct {
+	err error
+}
+
+func (e *pingSetupError) Error() string {
+	return e.err.Error()
+}
+
+func (e *pingSetupError) Unwrap() error {
+	return e.err
+}
+
+// Sends one probe and returns round trip time. Probe must give up when ctx
+// is done
+type pingProber func(ctx context.Context) (time.Duration, error)
+
+// Returns prober of address: host for ICMP, host:port for TCP and URL for
+// HTTP
+func newPingProber(probe PingProbe, address string) pingProber {
+	switch probe {
+	case PingTCP:
+		return func(ctx context.Context) (time.Duration, error) {
+			return pingTCP(ctx, address)
+		}
+	case PingHTTP:
+		// NOTE: Connection is kept alive, so only the first probe pays for
+		// handshake
+		client := &http.Client{}
+		return func(ctx context.Context) (time.Duration, error) {
+			return pingHTTP(ctx, client, address)
+		}
+	}
+	var seq uint16
+	return func(ctx context.Context) (time.Duration, error) {
+		seq++
+		return pingICMP(ctx, address, seq)
+	}
+}
+
+func pingTCP(ctx context.Context, address string) (time.Duration, error) {
+	var dialer net.Dialer
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return 0, err
+	}
+	rtt := time.Since(start)
+	conn.Close()
+	return rtt, nil
+}
+
+// NOTE: Any response counts, server errors still show it's reachable
+func pingHTTP(ctx context.Context, client *http.Client, url string) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	rtt := time.Since(start)
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return rtt, nil
+}
+
+// Opens unprivileged datagram ICMP socket where available, falls back to
+// raw socket requiring privileges. Returns connection and destination
+func listenICMP(ip net.IP) (*icmp.PacketConn, net.Addr, error) {
+	conn, err := icmp.ListenPacket("udp4", "0.0.0.0")
+	if err == nil {
+		return conn, &net.UDPAddr{IP: ip}, nil
+	}
+	conn, rawErr := icmp.ListenPacket("ip4:icmp", "0.0.0.0")
+	if rawErr != nil {
+		return nil, nil, &pingSetupError{fmt.Errorf(
+			"failed to open ICMP socket: %w", errors.Join(err, rawErr),
+		)}
+	}
+	return conn, &net.IPAddr{IP: ip}, nil
+}
+
+func pingICMP(ctx context.Context, host string, seq uint16) (time.Duration, error) {
+	ips, err := net.DefaultResolver.LookupIP(ctx, "ip4", host)
+	if err != nil {
+		return 0, err
+	}
+	conn, dst, err := listenICMP(ips[0])
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	msg := icmp.Message{
+		Type: ipv4.ICMPTypeEcho,
+		Body: &icmp.Echo{
+			ID:   os.Getpid() & 0xffff,
+			Seq:  int(seq),
+			Data: []byte("govermon"),
+		},
+	}
+	packet, err := msg.Marshal(nil)
+	if err != nil {
+		return 0, err
+	}
+
+	start := time.Now()
+	if _, err := conn.WriteTo(packet, dst); err != nil {
+		return 0, err
+	}
+	buf := make([]byte, 1500)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			return 0, err
+		}
+		reply, err := icmp.ParseMessage(ipv4.ICMPTypeEchoReply.Protocol(), buf[:n])
+		if err != nil {
+			continue
+		}
+		// NOTE: Kernel replaces ID of datagram sockets, raw sockets receive
+		// replies to other processes too, so sequence is checked only
+		echo, ok := reply.Body.(*icmp.Echo)
+		if reply.Type == ipv4.ICMPTypeEchoReply && ok && echo.Seq == int(seq) {
+			return time.Since(start), nil
+		}
+	}
+}
+
+type pingResult struct {
+	rtt time.Duration
+	err error
+}
+
+// Probes address once per tick in background, so slow targets don't delay
+// other collectors. RTT and jitter are in milliseconds, loss is percent of
+// the last probes. RTT of a lost probe, or one still running, is a gap
+type PingCollector struct {
+	Collector
+
+	Name    string
+	Probe   PingProbe
+	Address string
+
+	prober  pingProber
+	timeout time.Duration
+
+	results chan pingResult
+	pending bool
+
+	// NOTE: Outcomes of the last probes, true if lost
+	lost    []bool
+	lastRTT float64
+	jitter  float64
+
+	RTT    Entry `json:"rtt"`
+	Jitter Entry `json:"jitter"`
+	Loss   Entry `json:"loss"`
+}
+
+func NewPingCollector(
+	name string, probe PingProbe, address string, timeout time.Duration, size int,
+) *PingCollector {
+	if size < 1 {
+		panic("size must be greater than zero")
+	}
+	if timeout <= 0 {
+		timeout = DefaultPingTimeout
+	}
+	return &PingCollector{
+		Collector: Collector{size: size},
+
+		Name:    name,
+		Probe:   probe,
+		Address: address,
+
+		prober:  newPingProber(probe, address),
+		timeout: timeout,
+
+		// NOTE: Buffered, so probe finishes even if collector is inactive
+		results: make(chan pingResult, 1),
+
+		lastRTT: math.NaN(),
+		jitter:  math.NaN(),
+
+		RTT:    *NewEntry(size),
+		Jitter: *NewEntry(size),
+		Loss:   *NewEntry(size),
+	}
+}
+
+func (c *PingCollector) probe() {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	rtt, err := c.prober(ctx)
+	c.results <- pingResult{rtt, err}
+}
+
+// Updates jitter and loss with result and returns RTT in milliseconds, NaN
+// if probe is lost
+func (c *PingCollector) record(result pingResult) float64 {
+	lost := result.err != nil
+	if len(c.lost) == pingLossWindow {
+		c.lost = c.lost[1:]
+	}
+	c.lost = append(c.lost, lost)
+	if lost {
+		return math.NaN()
+	}
+
+	rtt := float64(result.rtt) / float64(time.Millisecond)
+	if !math.IsNaN(c.lastRTT) {
+		diff := math.Abs(rtt - c.lastRTT)
+		if math.IsNaN(c.jitter) {
+			c.jitter = diff
+		} else {
+			c.jitter += (diff - c.jitter) * pingJitterGain
+		}
+	}
+	c.lastRTT = rtt
+	return rtt
+}
+
+// Returns percent of lost probes in window, NaN before the first result
+func (c *PingCollector) lossPercent() float64 {
+	if len(c.lost) == 0 {
+		return math.NaN()
+	}
+	lost := 0
+	for _, l := range c.lost {
+		if l {
+			lost++
+		}
+	}
+	return float64(lost) / float64(len(c.lost)) * 100
+}
+
+// Records result of the previous probe and starts the next one. Probe
+// running longer than a tick, i.e. when timeout exceeds update rate, leaves
+// a gap. Probe that couldn't be sent is returned as error, not counted as lost
+func (c *PingCollector) Collect() error {
+	if !HasActiveEntries(c) {
+		return nil
+	}
+
+	rtt := math.NaN()
+	var err error
+	select {
+	case result := <-c.results:
+		c.pending = false
+		var setupErr *pingSetupError
+		if errors.As(result.err, &setupErr) {
+			err = fmt.Errorf("failed to probe %s: %w", c.Name, result.err)
+		} else {
+			rtt = c.record(result)
+		}
+	default:
+	}
+	if !c.pending {
+		c.pending = true
+		go c.probe()
+	}
+
+	MapValues([]valToEntry{
+		{rtt, &c.RTT},
+		{c.jitter, &c.Jitter},
+		{c.lossPercent(), &c.Loss},
+	})
+
+	return err
+}
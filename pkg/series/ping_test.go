@@ -0,0 +1,207 @@
+package series
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePingProbe(t *testing.T) {
+	for _, probe := range []PingProbe{PingICMP, PingTCP, PingHTTP} {
+		got, err := ParsePingProbe(probe.String())
+		if probe == PingICMP && runtime.GOOS == "windows" {
+			assert.ErrorContains(t, err, "not supported on windows")
+			continue
+		}
+		require.NoError(t, err)
+		assert.Equal(t, probe, got)
+	}
+	_, err := ParsePingProbe("udp")
+	assert.Error(t, err)
+}
+
+func TestPingCollector_record(t *testing.T) {
+	c := NewPingCollector("test", PingTCP, "", 0, 2)
+	lost := pingResult{err: context.DeadlineExceeded}
+	ms := func(v float64) pingResult {
+		return pingResult{rtt: time.Duration(v * float64(time.Millisecond))}
+	}
+
+	assert.True(t, math.IsNaN(c.lossPercent()))
+
+	tests := []struct {
+		name   string
+		result pingResult
+		rtt    float64
+		jitter float64
+		loss   float64
+	}{
+		{"First", ms(10), 10, math.NaN(), 0},
+		{"Second", ms(26), 26, 16, 0},
+		{"Lost", lost, math.NaN(), 16, 100.0 / 3},
+		{"Smoothed", ms(10), 10, 16, 25},
+		{"Smoothed change", ms(42), 42, 17, 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rtt := c.record(tt.result)
+			for _, v := range []struct{ want, got float64 }{
+				{tt.rtt, rtt},
+				{tt.jitter, c.jitter},
+				{tt.loss, c.lossPercent()},
+			} {
+				if math.IsNaN(v.want) {
+					assert.True(t, math.IsNaN(v.got))
+				} else {
+					assert.InDelta(t, v.want, v.got, 1e-9)
+				}
+			}
+		})
+	}
+
+	t.Run("Window", func(t *testing.T) {
+		for range pingLossWindow {
+			c.record(lost)
+		}
+		assert.Len(t, c.lost, pingLossWindow)
+		assert.Equal(t, 100.0, c.lossPercent())
+	})
+}
+
+func newTestListener(t *testing.T) net.Listener {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	return listener
+}
+
+func TestPingProbes(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	t.Run("TCP", func(t *testing.T) {
+		listener := newTestListener(t)
+		rtt, err := newPingProber(PingTCP, listener.Addr().String())(ctx)
+		require.NoError(t, err)
+		assert.Positive(t, rtt)
+
+		addr := listener.Addr().String()
+		listener.Close()
+		_, err = newPingProber(PingTCP, addr)(ctx)
+		assert.Error(t, err)
+	})
+
+	t.Run("HTTP", func(t *testing.T) {
+		var method string
+		server := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				method = r.Method
+				w.WriteHeader(http.StatusServiceUnavailable)
+			},
+		))
+		defer server.Close()
+
+		rtt, err := newPingProber(PingHTTP, server.URL)(ctx)
+		require.NoError(t, err)
+		assert.Positive(t, rtt)
+		assert.Equal(t, http.MethodHead, method)
+	})
+
+	t.Run("ICMP", func(t *testing.T) {
+		conn, _, err := listenICMP(net.IPv4(127, 0, 0, 1))
+		if err != nil {
+			t.Skip("ICMP sockets are not permitted:", err)
+		}
+		conn.Close()
+
+		rtt, err := newPingProber(PingICMP, "127.0.0.1")(ctx)
+		require.NoError(t, err)
+		assert.Positive(t, rtt)
+	})
+}
+
+func TestPingCollector_Collect(t *testing.T) {
+	t.Run("Reachable", func(t *testing.T) {
+		listener := newTestListener(t)
+		c := NewPingCollector("test", PingTCP, listener.Addr().String(), time.Second, 2)
+		sub := &Subscriber{}
+		c.RTT.Subscribe(sub)
+		c.Loss.Subscribe(sub)
+
+		require.NoError(t, c.Collect())
+		// NOTE: Result of the first probe is recorded on the next tick
+		assert.True(t, math.IsNaN(c.RTT.GetData().GetFirstValue()))
+
+		require.Eventually(t, func() bool {
+			require.NoError(t, c.Collect())
+			return !math.IsNaN(c.RTT.GetData().GetFirstValue())
+		}, time.Second, 10*time.Millisecond)
+		assert.Equal(t, 0.0, c.Loss.GetData().GetFirstValue())
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-release:
+				case <-r.Context().Done():
+				}
+			},
+		))
+		defer server.Close()
+		defer close(release)
+
+		timeout := 50 * time.Millisecond
+		c := NewPingCollector("test", PingHTTP, server.URL, timeout, 2)
+		sub := &Subscriber{}
+		c.RTT.Subscribe(sub)
+		c.Loss.Subscribe(sub)
+
+		start := time.Now()
+		require.NoError(t, c.Collect())
+		assert.Less(t, time.Since(start), timeout, "collect waits for probe")
+
+		require.Eventually(t, func() bool {
+			require.NoError(t, c.Collect())
+			return c.Loss.GetData().GetFirstValue() == 100
+		}, time.Second, 10*time.Millisecond)
+		assert.True(t, math.IsNaN(c.RTT.GetData().GetFirstValue()))
+	})
+}
+
+func TestPingCollector_Collect_SetupError(t *testing.T) {
+	c := NewPingCollector("test", PingICMP, "127.0.0.1", 0, 2)
+	c.prober = func(ctx context.Context) (time.Duration, error) {
+		return 0, &pingSetupError{errors.New("socket is not permitted")}
+	}
+	c.Loss.Subscribe(&Subscriber{})
+
+	require.NoError(t, c.Collect())
+	require.Eventually(t, func() bool {
+		return len(c.results) > 0
+	}, time.Second, time.Millisecond)
+	assert.ErrorContains(t, c.Collect(), "socket is not permitted")
+	// NOTE: Probe that was not sent is not lost
+	assert.Empty(t, c.lost)
+	assert.True(t, math.IsNaN(c.Loss.GetLatestValue()))
+}
+
+func TestPingCollector_Inactive(t *testing.T) {
+	c := NewPingCollector("test", PingTCP, "127.0.0.1:1", 0, 2)
+	c.prober = func(ctx context.Context) (time.Duration, error) {
+		return 0, errors.New("must not be called")
+	}
+	require.NoError(t, c.Collect())
+	assert.False(t, c.pending)
+}
@@ -0,0 +1,193 @@
+package series
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"slices"
+)
+
+// Battery status values reported by power_supply class
+const (
+	batteryCharging    = "Charging"
+	batteryDischarging = "Discharging"
+)
+
+type BatteryStats struct {
+	Name string
+
+	dir string
+
+	// Percent of full charge
+	Capacity Entry `json:"capacity"`
+	// Watts, positive while charging and negative while discharging
+	Rate Entry `json:"rate"`
+	// Seconds until empty while discharging or until full while charging
+	TimeRemaining Entry `json:"time_remaining"`
+}
+
+func NewBatteryStats(size int, name, dir string) *BatteryStats {
+	if size < 1 {
+		panic("size must be greater than zero")
+	}
+	return &BatteryStats{
+		Name: name,
+
+		dir: dir,
+
+		Capacity:      *NewEntry(size),
+		Rate:          *NewEntry(size),
+		TimeRemaining: *NewEntry(size),
+	}
+}
+
+// Returns capacity, signed rate and remaining time read from battery
+// directory. Missing values are NaN
+func readBattery(dir string) (capacity, rate, remaining float64) {
+	attr := func(name string, scale float64) float64 {
+		return readSysfsScaled(filepath.Join(dir, name), scale)
+	}
+	status, _ := readSysfsString(filepath.Join(dir, "status"))
+
+	// NOTE: Drivers report either energy in µWh and power in µW, or charge in
+	// µAh and current in µA. Voltage converts the latter
+	voltage := attr("voltage_now", 1e-6)
+	power := attr("power_now", 1e-6)
+	if math.IsNaN(power) {
+		power = attr("current_now", 1e-6) * voltage
+	}
+	energy, energyFull := attr("energy_now", 1e-6), attr("energy_full", 1e-6)
+	if math.IsNaN(energy) {
+		energy = attr("charge_now", 1e-6) * voltage
+		energyFull = attr("charge_full", 1e-6) * voltage
+	}
+
+	capacity = attr("capacity", 1)
+	if math.IsNaN(capacity) {
+		capacity = energy / energyFull * 100
+	}
+
+	// NOTE: Some drivers report signed values
+	rate = math.Abs(power)
+	if status == batteryDischarging {
+		rate = -rate
+	}
+
+	remaining = math.NaN()
+	switch status {
+	case batteryDischarging:
+		remaining = attr("time_to_empty_now", 1)
+		if math.IsNaN(remaining) && power != 0 {
+			remaining = energy / math.Abs(power) * 3600
+		}
+	case batteryCharging:
+		remaining = attr("time_to_full_now", 1)
+		if math.IsNaN(remaining) && power != 0 {
+			remaining = (energyFull - energy) / math.Abs(power) * 3600
+		}
+	}
+
+	return capacity, rate, remaining
+}
+
+// Reads batteries and AC adapters from power_supply class of sysfs.
+// Supplies are found by Discover, batteries gone after it are recorded as
+// gaps
+type PowerSupplyCollector struct {
+	Collector
+
+	root string
+
+	// NOTE: Directories of mains adapters, AC is online if any of them is
+	adapters []string
+
+	Batteries map[string]*BatteryStats
+
+	// 1 while on mains power, NaN if there are no adapters
+	AC Entry `json:"ac"`
+}
+
+// Returns collector reading sysfs mounted at root, normally SysfsRoot
+func NewPowerSupplyCollector(root string, size int) *PowerSupplyCollector {
+	if size < 1 {
+		panic("size must be greater than zero")
+	}
+	return &PowerSupplyCollector{
+		Collector: Collector{size: size},
+
+		root: root,
+
+		Batteries: map[string]*BatteryStats{},
+
+		AC: *NewEntry(size),
+	}
+}
+
+// Returns true if collector has mains adapters to report AC state of
+func (c *PowerSupplyCollector) HasAC() bool {
+	return len(c.adapters) > 0
+}
+
+// Finds batteries and mains adapters. Batteries of peripherals, i.e.
+// wireless mice, are skipped
+func (c *PowerSupplyCollector) Discover() error {
+	dirs, err := filepath.Glob(filepath.Join(c.root, "class/power_supply/*"))
+	if err != nil {
+		return fmt.Errorf("failed to discover power supplies: %w", err)
+	}
+
+	for _, dir := range dirs {
+		supplyType, err := readSysfsString(filepath.Join(dir, "type"))
+		if err != nil {
+			continue
+		}
+		switch supplyType {
+		case "Mains":
+			if !slices.Contains(c.adapters, dir) {
+				c.adapters = append(c.adapters, dir)
+			}
+		case "Battery":
+			scope, _ := readSysfsString(filepath.Join(dir, "scope"))
+			if scope == "Device" {
+				continue
+			}
+			name := filepath.Base(dir)
+			if _, present := c.Batteries[name]; !present {
+				c.Batteries[name] = NewBatteryStats(c.size, name, dir)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *PowerSupplyCollector) Collect() error {
+	if c.AC.IsActive() {
+		ac := math.NaN()
+		for _, adapter := range c.adapters {
+			online := readSysfsScaled(filepath.Join(adapter, "online"), 1)
+			if math.IsNaN(ac) || online > ac {
+				ac = online
+			}
+		}
+		MapValues([]valToEntry{
+			{ac, &c.AC},
+		})
+	}
+
+	for _, battery := range c.Batteries {
+		if !HasActiveEntries(battery) {
+			continue
+		}
+
+		capacity, rate, remaining := readBattery(battery.dir)
+
+		MapValues([]valToEntry{
+			{capacity, &battery.Capacity},
+			{rate, &battery.Rate},
+			{remaining, &battery.TimeRemaining},
+		})
+	}
+
+	return nil
+}
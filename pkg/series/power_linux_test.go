@@ -0,0 +1,84 @@
+package series
+
+import (
+	"maps"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPowerSupplyCollector(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.CopyFS(root, os.DirFS("testdata/sysfs")))
+
+	c := NewPowerSupplyCollector(root, 2)
+	require.NoError(t, c.Discover())
+	// NOTE: Mouse battery is skipped, USB supply is not an AC adapter. Its
+	// fixture has "-" in place of ":" of kernel name, which Windows rejects
+	assert.Equal(t, []string{"BAT0", "BAT1"}, slices.Sorted(maps.Keys(c.Batteries)))
+	assert.True(t, c.HasAC())
+
+	sub := &Subscriber{}
+	c.AC.Subscribe(sub)
+	for _, battery := range c.Batteries {
+		for _, entry := range GetEntries(battery) {
+			entry.Subscribe(sub)
+		}
+	}
+	require.NoError(t, c.Collect())
+
+	assert.Equal(t, 1.0, c.AC.GetData().GetFirstValue())
+	tests := []struct {
+		name      string
+		capacity  float64
+		rate      float64
+		remaining float64
+	}{
+		// NOTE: Energy and power, (50-31)Wh to full at 15W
+		{"BAT0", 62, 15, 4560},
+		// NOTE: Charge and current, 2.5Ah of 5Ah at 8V and 1A
+		{"BAT1", 50, -8, 9000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			battery := c.Batteries[tt.name]
+			assert.InDelta(t, tt.capacity, battery.Capacity.GetData().GetFirstValue(), 1e-9)
+			assert.InDelta(t, tt.rate, battery.Rate.GetData().GetFirstValue(), 1e-9)
+			assert.InDelta(t, tt.remaining, battery.TimeRemaining.GetData().GetFirstValue(), 1e-6)
+		})
+	}
+
+	t.Run("Discover again", func(t *testing.T) {
+		require.NoError(t, c.Discover())
+		assert.Len(t, c.Batteries, 2)
+		assert.Len(t, c.adapters, 1)
+	})
+
+	t.Run("Removed", func(t *testing.T) {
+		supplies := filepath.Join(root, "class/power_supply")
+		require.NoError(t, os.RemoveAll(filepath.Join(supplies, "BAT1")))
+		require.NoError(t, os.WriteFile(filepath.Join(supplies, "AC/online"), []byte("0\n"), 0o644))
+		require.NoError(t, c.Collect())
+
+		assert.Equal(t, 0.0, c.AC.GetData().GetFirstValue())
+		for _, entry := range GetEntries(c.Batteries["BAT1"]) {
+			assert.True(t, math.IsNaN(entry.GetData().GetFirstValue()))
+		}
+	})
+}
+
+func TestPowerSupplyCollector_NoSupplies(t *testing.T) {
+	c := NewPowerSupplyCollector(t.TempDir(), 2)
+	require.NoError(t, c.Discover())
+	assert.Empty(t, c.Batteries)
+	assert.False(t, c.HasAC())
+
+	c.AC.Subscribe(&Subscriber{})
+	require.NoError(t, c.Collect())
+	assert.True(t, math.IsNaN(c.AC.GetData().GetFirstValue()))
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package series
+
+// NOTE: Power supplies are not implemented for this platform, the types
+// exist so stats compile everywhere
+type BatteryStats struct {
+	Name string
+}
+
+type PowerSupplyCollector struct {
+	Collector
+
+	Batteries map[string]*BatteryStats
+}
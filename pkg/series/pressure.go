@@ -0,0 +1,75 @@
+package series
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Share of time tasks were stalled, in percent over 10, 60 and 300 seconds,
+// and total stall time in microseconds
+type PressureStats struct {
+	Avg10  float64
+	Avg60  float64
+	Avg300 float64
+	Total  float64
+}
+
+// Pressure Stall Information of a resource. Some is time at least one task
+// was stalled, full is time all non-idle tasks were stalled
+type Pressure struct {
+	Some PressureStats
+	Full PressureStats
+}
+
+func nanPressureStats() PressureStats {
+	return PressureStats{math.NaN(), math.NaN(), math.NaN(), math.NaN()}
+}
+
+// Parses data in /proc/pressure/* format. Missing lines are NaN, e.g. full
+// line of cpu before Linux 5.13
+func parsePressure(r io.Reader) (Pressure, error) {
+	pressure := Pressure{nanPressureStats(), nanPressureStats()}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		var stats *PressureStats
+		switch fields[0] {
+		case "some":
+			stats = &pressure.Some
+		case "full":
+			stats = &pressure.Full
+		default:
+			continue
+		}
+		for _, field := range fields[1:] {
+			key, value, _ := strings.Cut(field, "=")
+			num, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return Pressure{}, fmt.Errorf(
+					"failed to parse pressure %s %s: %w", fields[0], key, err,
+				)
+			}
+			switch key {
+			case "avg10":
+				stats.Avg10 = num
+			case "avg60":
+				stats.Avg60 = num
+			case "avg300":
+				stats.Avg300 = num
+			case "total":
+				stats.Total = num
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Pressure{}, fmt.Errorf("failed to read pressure: %w", err)
+	}
+	return pressure, nil
+}
@@ -0,0 +1,51 @@
+package series
+
+import (
+	"math"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePressure(t *testing.T) {
+	tests := []struct {
+		name     string
+		fixture  string
+		wantSome PressureStats
+		wantFull PressureStats
+	}{
+		{
+			"some and full", "testdata/pressure/memory",
+			PressureStats{1.53, 0.87, 0.25, 3459103},
+			PressureStats{0.41, 0.22, 0.06, 1271430},
+		},
+		{
+			"no full line", "testdata/pressure/cpu",
+			PressureStats{12.40, 8.05, 3.11, 98123456},
+			nanPressureStats(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := os.Open(tt.fixture)
+			require.NoError(t, err)
+			defer file.Close()
+
+			pressure, err := parsePressure(file)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSome, pressure.Some)
+			if math.IsNaN(tt.wantFull.Avg10) {
+				assert.True(t, math.IsNaN(pressure.Full.Avg10))
+				assert.True(t, math.IsNaN(pressure.Full.Total))
+			} else {
+				assert.Equal(t, tt.wantFull, pressure.Full)
+			}
+		})
+	}
+
+	_, err := parsePressure(strings.NewReader("some avg10=x\n"))
+	assert.ErrorContains(t, err, "avg10")
+}
@@ -2,14 +2,18 @@ package series
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/shirou/gopsutil/v4/process"
 )
 
+// CPU usage of a process. Times are percent of one core spent in each time
+// type since the previous collection
 type ProcessCPUCollector struct {
 	Collector
 
-	proc *process.Process
+	proc     *process.Process
+	counters *Counters
 
 	Sys       Entry `json:"sys"`
 	User      Entry `json:"user"`
@@ -32,7 +36,8 @@ func NewProcessCPUCollector(proc *process.Process, size int) *ProcessCPUCollecto
 	collector := ProcessCPUCollector{
 		Collector: Collector{size: size},
 
-		proc: proc,
+		proc:     proc,
+		counters: NewCounters(),
 
 		Sys:       *NewEntry(size),
 		User:      *NewEntry(size),
@@ -50,6 +55,13 @@ func NewProcessCPUCollector(proc *process.Process, size int) *ProcessCPUCollecto
 	return &collector
 }
 
+// Sets monitored process. Times of the previous process are forgotten, so
+// rates are not calculated between different processes
+func (c *ProcessCPUCollector) setProcess(proc *process.Process) {
+	c.proc = proc
+	c.counters.Reset()
+}
+
 func (c *ProcessCPUCollector) Collect() error {
 	if !HasActiveEntries(c) {
 		return nil
@@ -60,7 +72,7 @@ func (c *ProcessCPUCollector) Collect() error {
 		return fmt.Errorf("failed to get cpu times: %w", err)
 	}
 
-	MapValuesWithDiff([]valToEntry{
+	c.counters.MapRates([]valToEntry{
 		{times.System, &c.Sys},
 		{times.User, &c.User},
 		{times.Idle, &c.Idle},
@@ -71,14 +83,14 @@ func (c *ProcessCPUCollector) Collect() error {
 		{times.Softirq, &c.Softirq},
 		{times.Nice, &c.Nice},
 		{times.GuestNice, &c.GuestNice},
-	})
+	}, time.Now(), 100)
 
 	if c.Perc.IsActive() {
 		perc, err := c.proc.CPUPercent()
 		if err != nil {
 			return fmt.Errorf("failed to get cpu percent: %w", err)
 		}
-		c.Perc.data.AddValues(perc)
+		c.Perc.AddValue(perc)
 	}
 
 	return nil
@@ -0,0 +1,233 @@
+package series
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// Criteria to find a process. Non-empty criteria must all match
+type ProcessMatcher struct {
+	PID      int32
+	Exe      string
+	ExeRegex *regexp.Regexp
+	Cmdline  string
+	// Creation time in milliseconds since epoch. Together with PID identifies
+	// a single process instance, PID alone may be reused by another process
+	CreateTime int64
+}
+
+func trimExeExt(name string) string {
+	return strings.TrimSuffix(strings.ToLower(name), ".exe")
+}
+
+// Returns true if proc matches all non-empty criteria. Exe is compared case
+// insensitive, with or without .exe extension
+func (m *ProcessMatcher) Match(proc *process.Process) (bool, error) {
+	if m.PID != 0 && proc.Pid != m.PID {
+		return false, nil
+	}
+
+	if m.Exe != "" || m.ExeRegex != nil {
+		name, err := proc.Name()
+		if err != nil {
+			return false, err
+		}
+		if m.Exe != "" && trimExeExt(name) != trimExeExt(m.Exe) {
+			return false, nil
+		}
+		if m.ExeRegex != nil && !m.ExeRegex.MatchString(name) {
+			return false, nil
+		}
+	}
+
+	if m.Cmdline != "" {
+		cmdline, err := proc.Cmdline()
+		if err != nil {
+			return false, err
+		}
+		if !strings.Contains(cmdline, m.Cmdline) {
+			return false, nil
+		}
+	}
+
+	if m.CreateTime != 0 {
+		createTime, err := proc.CreateTime()
+		if err != nil {
+			return false, err
+		}
+		if createTime != m.CreateTime {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (m *ProcessMatcher) IsEmpty() bool {
+	return m.PID == 0 && m.Exe == "" && m.ExeRegex == nil && m.Cmdline == "" &&
+		m.CreateTime == 0
+}
+
+// CPU and memory stats of a process found by matcher. Process is resolved
+// again when it exits, entries keep their history with a gap in between.
+// Process instance matched by create time is not resolved once it's gone
+type ProcessCollector struct {
+	Collector
+
+	Name string
+
+	matcher       ProcessMatcher
+	listProcesses func() ([]*process.Process, error)
+
+	proc *process.Process
+	// Process instance matched by create time exited
+	gone bool
+
+	CPU *ProcessCPUCollector
+	Mem *ProcessMemCollector
+}
+
+func NewProcessCollector(name string, matcher ProcessMatcher, size int) *ProcessCollector {
+	if size < 1 {
+		panic("size must be greater than zero")
+	}
+	if matcher.IsEmpty() {
+		panic("process matcher must have at least one criterion")
+	}
+	collector := ProcessCollector{
+		Collector: Collector{size: size},
+
+		Name: name,
+
+		matcher:       matcher,
+		listProcesses: process.Processes,
+
+		CPU: NewProcessCPUCollector(nil, size),
+		Mem: NewProcessMemCollector(nil, size),
+	}
+	return &collector
+}
+
+// Returns currently monitored process, nil if not found
+func (c *ProcessCollector) GetProcess() *process.Process {
+	return c.proc
+}
+
+// Returns the oldest process matching the matcher, nil if not found
+func findOldest(procs []*process.Process, matcher *ProcessMatcher) *process.Process {
+	var found *process.Process
+	var foundCreateTime int64
+	for _, proc := range procs {
+		// NOTE: Processes may exit while we iterate, errors are expected
+		match, err := matcher.Match(proc)
+		if err != nil || !match {
+			continue
+		}
+		createTime, err := proc.CreateTime()
+		if err != nil {
+			continue
+		}
+		if found == nil || createTime < foundCreateTime {
+			found, foundCreateTime = proc, createTime
+		}
+	}
+	return found
+}
+
+func (c *ProcessCollector) resolve() (*process.Process, error) {
+	procs, err := c.listProcesses()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes: %w", err)
+	}
+	return findOldest(procs, &c.matcher), nil
+}
+
+func (c *ProcessCollector) setProcess(proc *process.Process) {
+	c.proc = proc
+	c.CPU.setProcess(proc)
+	c.Mem.proc = proc
+}
+
+// Adds NaN to active entries, so plots show a gap while process is missing
+func addGap(entries map[string]*Entry) {
+	mapping := make([]valToEntry, 0, len(entries))
+	for _, entry := range entries {
+		mapping = append(mapping, valToEntry{math.NaN(), entry})
+	}
+	MapValues(mapping)
+}
+
+// Adds gap to active entries, e.g. after collection was paused
+func (c *ProcessCollector) MarkGap() {
+	// NOTE: Rates after the gap must not average the paused time
+	c.CPU.counters.Reset()
+	addGap(GetEntries(c.CPU))
+	addGap(GetEntries(c.Mem))
+}
+
+func (c *ProcessCollector) isRunning() bool {
+	return isProcessRunning(c.proc)
+}
+
+// Forgets exited process and adds gaps to entries not collected yet
+func (c *ProcessCollector) lost(entries ...map[string]*Entry) {
+	c.setProcess(nil)
+	c.gone = c.matcher.CreateTime != 0
+	for _, e := range entries {
+		addGap(e)
+	}
+}
+
+func (c *ProcessCollector) Collect() error {
+	if !HasActiveEntries(c.CPU) && !HasActiveEntries(c.Mem) {
+		return nil
+	}
+
+	cpuEntries, memEntries := GetEntries(c.CPU), GetEntries(c.Mem)
+
+	// NOTE: Restarted process is resolved on the next tick, so CPU times
+	// diff is not calculated between different processes
+	if c.proc != nil && !c.isRunning() {
+		c.lost(cpuEntries, memEntries)
+		return nil
+	}
+	if c.proc == nil {
+		if c.gone {
+			c.MarkGap()
+			return nil
+		}
+		proc, err := c.resolve()
+		if err != nil {
+			return err
+		}
+		if proc == nil {
+			c.gone = c.matcher.CreateTime != 0
+			c.MarkGap()
+			return nil
+		}
+		c.setProcess(proc)
+	}
+
+	err := c.CPU.Collect()
+	if err != nil {
+		if !c.isRunning() {
+			c.lost(cpuEntries, memEntries)
+			return nil
+		}
+		return fmt.Errorf("failed to collect cpu of process %s: %w", c.Name, err)
+	}
+	err = c.Mem.Collect()
+	if err != nil {
+		if !c.isRunning() {
+			c.lost(memEntries)
+			return nil
+		}
+		return fmt.Errorf("failed to collect memory of process %s: %w", c.Name, err)
+	}
+
+	return nil
+}
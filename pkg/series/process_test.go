@@ -0,0 +1,157 @@
+package series
+
+import (
+	"math"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/shirou/gopsutil/v4/process"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessMatcher_Match(t *testing.T) {
+	self, err := process.NewProcess(int32(os.Getpid()))
+	require.NoError(t, err)
+	name, err := self.Name()
+	require.NoError(t, err)
+	createTime, err := self.CreateTime()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		matcher ProcessMatcher
+		want    bool
+	}{
+		{
+			name:    "PID",
+			matcher: ProcessMatcher{PID: self.Pid},
+			want:    true,
+		},
+		{
+			name:    "Other PID",
+			matcher: ProcessMatcher{PID: self.Pid + 1},
+			want:    false,
+		},
+		{
+			name:    "Exe",
+			matcher: ProcessMatcher{Exe: strings.ToUpper(name) + ".exe"},
+			want:    true,
+		},
+		{
+			name:    "Exe regex",
+			matcher: ProcessMatcher{ExeRegex: regexp.MustCompile("^" + regexp.QuoteMeta(name[:2]))},
+			want:    true,
+		},
+		{
+			name:    "Cmdline",
+			matcher: ProcessMatcher{Cmdline: "-test."},
+			want:    true,
+		},
+		{
+			name:    "PID and create time",
+			matcher: ProcessMatcher{PID: self.Pid, CreateTime: createTime},
+			want:    true,
+		},
+		{
+			name:    "Reused PID",
+			matcher: ProcessMatcher{PID: self.Pid, CreateTime: createTime - 1},
+			want:    false,
+		},
+		{
+			name:    "All criteria must match",
+			matcher: ProcessMatcher{PID: self.Pid, Cmdline: "not in cmdline"},
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.matcher.Match(self)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewProcessCollector_EmptyMatcher(t *testing.T) {
+	assert.Panics(t, func() { NewProcessCollector("empty", ProcessMatcher{}, 1) })
+}
+
+func TestProcessCollector_Collect_NotFound(t *testing.T) {
+	c := NewProcessCollector("none", ProcessMatcher{Cmdline: "not in cmdline"}, 2)
+	c.listProcesses = func() ([]*process.Process, error) { return nil, nil }
+	data := c.Mem.RSS.Subscribe(&Subscriber{})
+
+	require.NoError(t, c.Collect())
+	assert.Nil(t, c.GetProcess())
+	assert.True(t, math.IsNaN(data.GetFirstValue()))
+}
+
+func TestProcessCollector_Collect_Gone(t *testing.T) {
+	self, err := process.NewProcess(int32(os.Getpid()))
+	require.NoError(t, err)
+	createTime, err := self.CreateTime()
+	require.NoError(t, err)
+
+	// NOTE: Another create time stands for a process that reused the PID
+	matcher := ProcessMatcher{PID: self.Pid, CreateTime: createTime - 1}
+	c := NewProcessCollector("gone", matcher, 2)
+	listed := 0
+	c.listProcesses = func() ([]*process.Process, error) {
+		listed++
+		return []*process.Process{self}, nil
+	}
+	data := c.Mem.RSS.Subscribe(&Subscriber{})
+
+	require.NoError(t, c.Collect())
+	require.NoError(t, c.Collect())
+	assert.Nil(t, c.GetProcess())
+	assert.Equal(t, 1, listed)
+	assert.True(t, math.IsNaN(data.GetFirstValue()))
+}
+
+func TestProcessCollector_Collect_Restart(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sleep is not available")
+	}
+	sleepPath, err := exec.LookPath("sleep")
+	if err != nil {
+		t.Skip("sleep is not available")
+	}
+
+	// NOTE: Unique argument to not match sleeps of other processes
+	startSleep := func() *exec.Cmd {
+		cmd := exec.Command(sleepPath, "3600.4242")
+		require.NoError(t, cmd.Start())
+		t.Cleanup(func() {
+			cmd.Process.Kill()
+			cmd.Wait()
+		})
+		return cmd
+	}
+
+	c := NewProcessCollector("sleep", ProcessMatcher{Exe: "sleep", Cmdline: "3600.4242"}, 4)
+	data := c.Mem.RSS.Subscribe(&Subscriber{})
+
+	first := startSleep()
+	require.NoError(t, c.Collect())
+	require.NotNil(t, c.GetProcess())
+	assert.Equal(t, int32(first.Process.Pid), c.GetProcess().Pid)
+	assert.Greater(t, data.GetFirstValue(), 0.0)
+
+	require.NoError(t, first.Process.Kill())
+	first.Wait()
+	require.NoError(t, c.Collect())
+	assert.Nil(t, c.GetProcess())
+	assert.True(t, math.IsNaN(data.GetFirstValue()))
+
+	second := startSleep()
+	require.NoError(t, c.Collect())
+	require.NotNil(t, c.GetProcess())
+	assert.Equal(t, int32(second.Process.Pid), c.GetProcess().Pid)
+	assert.Greater(t, data.GetFirstValue(), 0.0)
+}
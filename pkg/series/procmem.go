@@ -45,7 +45,7 @@ func (c *ProcessMemCollector) Collect() error {
 		return nil
 	}
 
-	mem, err := c.proc.MemoryInfo()
+	mem, err := getMemoryInfo(c.proc)
 	if err != nil {
 		return fmt.Errorf("failed to get memory stats: %w", err)
 	}
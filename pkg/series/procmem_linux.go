@@ -0,0 +1,51 @@
+package series
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// Returns memory info with fields gopsutil leaves empty on Linux (HWM, Data,
+// Stack, Locked, Swap) taken from /proc/[pid]/status
+func getMemoryInfo(proc *process.Process) (*process.MemoryInfoStat, error) {
+	mem, err := proc.MemoryInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := os.ReadFile(fmt.Sprintf("/proc/%d/status", proc.Pid))
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]*uint64{
+		"VmHWM:":  &mem.HWM,
+		"VmData:": &mem.Data,
+		"VmStk:":  &mem.Stack,
+		"VmLck:":  &mem.Locked,
+		"VmSwap:": &mem.Swap,
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(status))
+	for scanner.Scan() {
+		parts := bytes.Fields(scanner.Bytes())
+		if len(parts) < 2 {
+			continue
+		}
+		field, present := fields[string(parts[0])]
+		if !present {
+			continue
+		}
+		// NOTE: Values are in kB
+		value, err := strconv.ParseUint(string(parts[1]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", parts[0], err)
+		}
+		*field = value * 1024
+	}
+
+	return mem, nil
+}
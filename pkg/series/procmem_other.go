@@ -0,0 +1,11 @@
+//go:build !linux
+
+package series
+
+import (
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+func getMemoryInfo(proc *process.Process) (*process.MemoryInfoStat, error) {
+	return proc.MemoryInfo()
+}
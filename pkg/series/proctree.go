@@ -0,0 +1,276 @@
+package series
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// Identifies process instance, PID alone may be reused
+type processKey struct {
+	pid        int32
+	createTime int64
+}
+
+// Stats of a child taking a place in top-N of the tree by RSS
+type ProcessTreeTop struct {
+	lock sync.Mutex
+	name string
+
+	RSS     Entry `json:"rss"`
+	CPUPerc Entry `json:"cpu_perc"`
+}
+
+// Returns name of the child currently at this place, empty if there is none
+func (t *ProcessTreeTop) GetName() string {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.name
+}
+
+func (t *ProcessTreeTop) setName(name string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.name = name
+}
+
+type treeProcessStats struct {
+	name string
+	mem  *process.MemoryInfoStat
+	perc float64
+}
+
+// Sums of memory and CPU usage of a process and all its descendants. Tree is
+// walked on each tick, so children started later are counted too
+type ProcessTreeCollector struct {
+	Collector
+
+	Name string
+
+	matcher       ProcessMatcher
+	listProcesses func() ([]*process.Process, error)
+
+	root *process.Process
+	// NOTE: CPU percent is calculated between calls on the same instance
+	procs map[processKey]*process.Process
+
+	Mem *ProcessMemCollector
+
+	CPUPerc  Entry `json:"cpu_perc"`
+	Children Entry `json:"children"`
+
+	Top []*ProcessTreeTop
+}
+
+// Returns tree collector with root found by matcher. topN children with the
+// largest RSS get their own entries, 0 disables them
+func NewProcessTreeCollector(
+	name string, matcher ProcessMatcher, topN int, size int,
+) *ProcessTreeCollector {
+	if size < 1 {
+		panic("size must be greater than zero")
+	}
+	if matcher.IsEmpty() {
+		panic("process matcher must have at least one criterion")
+	}
+	collector := ProcessTreeCollector{
+		Collector: Collector{size: size},
+
+		Name: name,
+
+		matcher:       matcher,
+		listProcesses: process.Processes,
+
+		procs: make(map[processKey]*process.Process),
+
+		Mem: NewProcessMemCollector(nil, size),
+
+		CPUPerc:  *NewEntry(size),
+		Children: *NewEntry(size),
+
+		Top: make([]*ProcessTreeTop, topN),
+	}
+	for x := range collector.Top {
+		collector.Top[x] = &ProcessTreeTop{
+			RSS:     *NewEntry(size),
+			CPUPerc: *NewEntry(size),
+		}
+	}
+	return &collector
+}
+
+// Returns current root process, nil if not found
+func (c *ProcessTreeCollector) GetProcess() *process.Process {
+	return c.root
+}
+
+func (c *ProcessTreeCollector) hasActiveEntries() bool {
+	if HasActiveEntries(c) || HasActiveEntries(c.Mem) {
+		return true
+	}
+	for _, top := range c.Top {
+		if HasActiveEntries(top) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *ProcessTreeCollector) markGap() {
+	addGap(GetEntries(c))
+	addGap(GetEntries(c.Mem))
+	for _, top := range c.Top {
+		top.setName("")
+		addGap(GetEntries(top))
+	}
+}
+
+// Returns root and its descendants, the root is the first one
+func getDescendants(root *process.Process, procs []*process.Process) []*process.Process {
+	children := make(map[int32][]*process.Process)
+	for _, proc := range procs {
+		ppid, err := proc.Ppid()
+		if err != nil || ppid == proc.Pid {
+			continue
+		}
+		children[ppid] = append(children[ppid], proc)
+	}
+
+	tree := []*process.Process{root}
+	for x := 0; x < len(tree); x++ {
+		tree = append(tree, children[tree[x].Pid]...)
+	}
+	return tree
+}
+
+// Returns cached instance of proc, so CPU percent has a previous value
+func (c *ProcessTreeCollector) getCached(
+	proc *process.Process, cache map[processKey]*process.Process,
+) (*process.Process, error) {
+	createTime, err := proc.CreateTime()
+	if err != nil {
+		return nil, err
+	}
+	key := processKey{proc.Pid, createTime}
+	cached, present := c.procs[key]
+	if !present {
+		cached = proc
+	}
+	cache[key] = cached
+	return cached, nil
+}
+
+func (c *ProcessTreeCollector) getStats(
+	proc *process.Process, cache map[processKey]*process.Process,
+) (treeProcessStats, error) {
+	proc, err := c.getCached(proc, cache)
+	if err != nil {
+		return treeProcessStats{}, err
+	}
+	mem, err := getMemoryInfo(proc)
+	if err != nil {
+		return treeProcessStats{}, err
+	}
+	perc, err := proc.Percent(0)
+	if err != nil {
+		return treeProcessStats{}, err
+	}
+	name, _ := proc.Name()
+	return treeProcessStats{name, mem, perc}, nil
+}
+
+func (c *ProcessTreeCollector) Collect() error {
+	if !c.hasActiveEntries() {
+		return nil
+	}
+
+	procs, err := c.listProcesses()
+	if err != nil {
+		return fmt.Errorf("failed to list processes: %w", err)
+	}
+
+	if c.root != nil && !isProcessRunning(c.root) {
+		c.root = nil
+	}
+	if c.root == nil {
+		c.root = findOldest(procs, &c.matcher)
+	}
+	if c.root == nil {
+		c.procs = make(map[processKey]*process.Process)
+		c.markGap()
+		return nil
+	}
+
+	cache := make(map[processKey]*process.Process)
+	tree := make([]treeProcessStats, 0)
+	for x, proc := range getDescendants(c.root, procs) {
+		stats, err := c.getStats(proc, cache)
+		if err != nil {
+			// NOTE: Processes may exit while we iterate, errors are expected
+			// for children. Root will be resolved again on the next tick
+			if x == 0 {
+				c.root = nil
+				c.procs = cache
+				c.markGap()
+				return nil
+			}
+			continue
+		}
+		tree = append(tree, stats)
+	}
+	c.procs = cache
+
+	var sum process.MemoryInfoStat
+	var perc float64
+	for _, stats := range tree {
+		sum.RSS += stats.mem.RSS
+		sum.VMS += stats.mem.VMS
+		sum.HWM += stats.mem.HWM
+		sum.Data += stats.mem.Data
+		sum.Stack += stats.mem.Stack
+		sum.Locked += stats.mem.Locked
+		sum.Swap += stats.mem.Swap
+		perc += stats.perc
+	}
+
+	MapValues([]valToEntry{
+		{float64(sum.RSS), &c.Mem.RSS},
+		{float64(sum.VMS), &c.Mem.VMS},
+		{float64(sum.HWM), &c.Mem.HWM},
+		{float64(sum.Data), &c.Mem.Data},
+		{float64(sum.Stack), &c.Mem.Stack},
+		{float64(sum.Locked), &c.Mem.Locked},
+		{float64(sum.Swap), &c.Mem.Swap},
+
+		{perc, &c.CPUPerc},
+		// NOTE: Root process is not a child
+		{float64(len(tree) - 1), &c.Children},
+	})
+
+	children := tree[1:]
+	slices.SortStableFunc(children, func(a, b treeProcessStats) int {
+		return cmp.Compare(b.mem.RSS, a.mem.RSS)
+	})
+	for x, top := range c.Top {
+		if x >= len(children) {
+			top.setName("")
+			addGap(GetEntries(top))
+			continue
+		}
+		top.setName(children[x].name)
+		MapValues([]valToEntry{
+			{float64(children[x].mem.RSS), &top.RSS},
+			{children[x].perc, &top.CPUPerc},
+		})
+	}
+
+	return nil
+}
+
+func isProcessRunning(proc *process.Process) bool {
+	running, err := proc.IsRunning()
+	return err == nil && running
+}
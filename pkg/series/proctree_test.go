@@ -0,0 +1,57 @@
+package series
+
+import (
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/process"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessTreeCollector_Collect(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sh is not available")
+	}
+	shPath, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh is not available")
+	}
+
+	// NOTE: Unique argument to not match other processes
+	cmd := exec.Command(shPath, "-c", "sleep 3600.4343 & sleep 3600.4343 & wait")
+	require.NoError(t, cmd.Start())
+	t.Cleanup(func() {
+		proc, err := process.NewProcess(int32(cmd.Process.Pid))
+		if err == nil {
+			children, _ := proc.Children()
+			for _, child := range children {
+				child.Kill()
+			}
+		}
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+
+	c := NewProcessTreeCollector(
+		"sh", ProcessMatcher{Exe: "sh", Cmdline: "3600.4343"}, 1, 4,
+	)
+	sub := &Subscriber{}
+	children := c.Children.Subscribe(sub)
+	rss := c.Mem.RSS.Subscribe(sub)
+	topRSS := c.Top[0].RSS.Subscribe(sub)
+
+	// NOTE: Children are started asynchronously
+	require.Eventually(t, func() bool {
+		require.NoError(t, c.Collect())
+		return children.GetFirstValue() == 2
+	}, 5*time.Second, 50*time.Millisecond)
+
+	require.NotNil(t, c.GetProcess())
+	assert.Equal(t, int32(cmd.Process.Pid), c.GetProcess().Pid)
+	assert.Equal(t, "sleep", c.Top[0].GetName())
+	assert.Greater(t, topRSS.GetFirstValue(), 0.0)
+	assert.Greater(t, rss.GetFirstValue(), topRSS.GetFirstValue())
+}
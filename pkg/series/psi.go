@@ -0,0 +1,132 @@
+package series
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const pressureRoot = "/proc/pressure"
+
+// Pressure Stall Information of one resource. Avg values are percent of time
+// tasks were stalled, rates are the same share calculated between ticks
+type PSIResourceCollector struct {
+	Collector
+
+	file string
+
+	counters *Counters
+
+	SomeAvg10  Entry `json:"some_avg10"`
+	SomeAvg60  Entry `json:"some_avg60"`
+	SomeAvg300 Entry `json:"some_avg300"`
+	SomeRate   Entry `json:"some_rate"`
+
+	FullAvg10  Entry `json:"full_avg10"`
+	FullAvg60  Entry `json:"full_avg60"`
+	FullAvg300 Entry `json:"full_avg300"`
+	FullRate   Entry `json:"full_rate"`
+}
+
+func newPSIResourceCollector(file string, size int) *PSIResourceCollector {
+	return &PSIResourceCollector{
+		Collector: Collector{size: size},
+
+		file: file,
+
+		counters: NewCounters(),
+
+		SomeAvg10:  *NewEntry(size),
+		SomeAvg60:  *NewEntry(size),
+		SomeAvg300: *NewEntry(size),
+		SomeRate:   *NewEntry(size),
+
+		FullAvg10:  *NewEntry(size),
+		FullAvg60:  *NewEntry(size),
+		FullAvg300: *NewEntry(size),
+		FullRate:   *NewEntry(size),
+	}
+}
+
+func (c *PSIResourceCollector) read() (Pressure, error) {
+	file, err := os.Open(c.file)
+	if err != nil {
+		return Pressure{}, err
+	}
+	defer file.Close()
+	return parsePressure(file)
+}
+
+func (c *PSIResourceCollector) collect(now time.Time) error {
+	if !HasActiveEntries(c) {
+		return nil
+	}
+
+	pressure, err := c.read()
+	// NOTE: Kernel may be built without PSI or have it disabled, resource
+	// is shown as a gap then
+	if errors.Is(err, fs.ErrNotExist) {
+		pressure = Pressure{nanPressureStats(), nanPressureStats()}
+	} else if err != nil {
+		return fmt.Errorf("failed to get pressure of %s: %w", c.file, err)
+	}
+
+	MapValues([]valToEntry{
+		{pressure.Some.Avg10, &c.SomeAvg10},
+		{pressure.Some.Avg60, &c.SomeAvg60},
+		{pressure.Some.Avg300, &c.SomeAvg300},
+
+		{pressure.Full.Avg10, &c.FullAvg10},
+		{pressure.Full.Avg60, &c.FullAvg60},
+		{pressure.Full.Avg300, &c.FullAvg300},
+	})
+	// NOTE: Totals are microseconds stalled, rates are percent of time
+	c.counters.MapRates([]valToEntry{
+		{pressure.Some.Total, &c.SomeRate},
+		{pressure.Full.Total, &c.FullRate},
+	}, now, 100/1e6)
+
+	return nil
+}
+
+// Pressure Stall Information of CPU, memory and IO. Available on Linux 4.20+
+type PSICollector struct {
+	Collector
+
+	CPU    *PSIResourceCollector
+	Memory *PSIResourceCollector
+	IO     *PSIResourceCollector
+}
+
+func NewPSICollector(size int) *PSICollector {
+	return newPSICollector(pressureRoot, size)
+}
+
+// Returns collector reading files from root instead of /proc/pressure
+func newPSICollector(root string, size int) *PSICollector {
+	if size < 1 {
+		panic("size must be greater than zero")
+	}
+	collector := PSICollector{
+		Collector: Collector{size: size},
+
+		CPU:    newPSIResourceCollector(filepath.Join(root, "cpu"), size),
+		Memory: newPSIResourceCollector(filepath.Join(root, "memory"), size),
+		IO:     newPSIResourceCollector(filepath.Join(root, "io"), size),
+	}
+	return &collector
+}
+
+func (c *PSICollector) Collect() error {
+	now := time.Now()
+	for _, resource := range []*PSIResourceCollector{c.CPU, c.Memory, c.IO} {
+		err := resource.collect(now)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package series
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPSICollector_Collect(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"cpu", "memory"} {
+		data, err := os.ReadFile(filepath.Join("testdata/pressure", name))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(root, name), data, 0o644))
+	}
+	// NOTE: io is missing, e.g. kernel without PSI
+
+	c := newPSICollector(root, 2)
+	sub := &Subscriber{}
+	for _, resource := range []*PSIResourceCollector{c.CPU, c.Memory, c.IO} {
+		for _, entry := range GetEntries(resource) {
+			entry.Subscribe(sub)
+		}
+	}
+
+	now := time.Now()
+	require.NoError(t, c.CPU.collect(now))
+	require.NoError(t, c.Memory.collect(now))
+	require.NoError(t, c.IO.collect(now))
+
+	assert.Equal(t, 12.40, c.CPU.SomeAvg10.GetData().GetFirstValue())
+	assert.Equal(t, 3.11, c.CPU.SomeAvg300.GetData().GetFirstValue())
+	assert.True(t, math.IsNaN(c.CPU.FullAvg10.GetData().GetFirstValue()))
+	assert.Equal(t, 0.41, c.Memory.FullAvg10.GetData().GetFirstValue())
+	assert.Equal(t, 0.22, c.Memory.FullAvg60.GetData().GetFirstValue())
+	assert.True(t, math.IsNaN(c.Memory.SomeRate.GetData().GetFirstValue()),
+		"rate of the first sample is unknown")
+	assert.True(t, math.IsNaN(c.IO.SomeAvg10.GetData().GetFirstValue()))
+
+	// NOTE: 0.5s of some and 0.1s of full stall over 1s
+	require.NoError(t, os.WriteFile(filepath.Join(root, "memory"), []byte(
+		"some avg10=1.53 avg60=0.87 avg300=0.25 total=3959103\n"+
+			"full avg10=0.41 avg60=0.22 avg300=0.06 total=1371430\n",
+	), 0o644))
+	require.NoError(t, c.Memory.collect(now.Add(time.Second)))
+	assert.InDelta(t, 50, c.Memory.SomeRate.GetData().GetFirstValue(), 1e-9)
+	assert.InDelta(t, 10, c.Memory.FullRate.GetData().GetFirstValue(), 1e-9)
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "memory"), []byte(
+		"some avg10=0.00 avg60=0.00 avg300=0.00 total=10\n",
+	), 0o644))
+	require.NoError(t, c.Memory.collect(now.Add(2*time.Second)))
+	assert.True(t, math.IsNaN(c.Memory.SomeRate.GetData().GetFirstValue()),
+		"counter reset must not produce a negative rate")
+}
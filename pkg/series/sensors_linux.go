@@ -0,0 +1,289 @@
+package series
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+type SensorKind int
+
+const (
+	SensorTemp SensorKind = iota
+	SensorFan
+	SensorVoltage
+	SensorPower
+)
+
+// hwmon attribute prefix and multiplier converting raw value to °C, RPM, V
+// and W respectively
+type sensorKind struct {
+	prefix string
+	scale  float64
+}
+
+var sensorKinds = []sensorKind{
+	SensorTemp:    {"temp", 1e-3},
+	SensorFan:     {"fan", 1},
+	SensorVoltage: {"in", 1e-3},
+	SensorPower:   {"power", 1e-6},
+}
+
+// Matches hwmon input attribute, i.e. temp1_input or power1_average
+var hwmonInputRe = regexp.MustCompile(`^(temp|fan|in|power)(\d+)_(input|average)$`)
+
+type SensorStats struct {
+	// NOTE: Chip and label joined, unique within collector
+	Name  string
+	Chip  string
+	Label string
+	Kind  SensorKind
+
+	// NOTE: Limits reported by the chip or thermal zone trip points, NaN if
+	// not provided
+	Min  float64
+	Max  float64
+	Crit float64
+
+	inputPath string
+	scale     float64
+
+	Value Entry `json:"value"`
+}
+
+func NewSensorStats(size int, chip, label string, kind SensorKind) *SensorStats {
+	if size < 1 {
+		panic("size must be greater than zero")
+	}
+	return &SensorStats{
+		Name:  chip + " " + label,
+		Chip:  chip,
+		Label: label,
+		Kind:  kind,
+
+		Min:  math.NaN(),
+		Max:  math.NaN(),
+		Crit: math.NaN(),
+
+		scale: sensorKinds[kind].scale,
+
+		Value: *NewEntry(size),
+	}
+}
+
+// Reads hwmon chips and thermal zones from sysfs. Sensors are found by
+// Discover, values of sensors gone after it are recorded as gaps
+type SensorsCollector struct {
+	Collector
+
+	root string
+
+	Sensors map[string]*SensorStats
+}
+
+// Returns collector reading sysfs mounted at root, normally SysfsRoot
+func NewSensorsCollector(root string, size int) *SensorsCollector {
+	if size < 1 {
+		panic("size must be greater than zero")
+	}
+	return &SensorsCollector{
+		Collector: Collector{size: size},
+
+		root: root,
+
+		Sensors: map[string]*SensorStats{},
+	}
+}
+
+// Returns scaled limit stored in attribute, NaN if it's missing. Chips
+// report zero for limits they don't support
+func readSensorLimit(path string, scale float64) float64 {
+	value, err := readSysfsFloat(path)
+	if err != nil || value == 0 {
+		return math.NaN()
+	}
+	return value * scale
+}
+
+// Adds sensor unless its input is known already. Sensors with the same name
+// are told apart by key of their device, i.e. two NVMe drives
+func (c *SensorsCollector) addSensor(sensor *SensorStats, key string) {
+	for _, known := range c.Sensors {
+		if known.inputPath == sensor.inputPath {
+			return
+		}
+	}
+	if _, present := c.Sensors[sensor.Name]; present {
+		sensor.Name = fmt.Sprintf("%s (%s)", sensor.Name, key)
+	}
+	c.Sensors[sensor.Name] = sensor
+}
+
+// Matches PCI address, i.e. 0000:01:00.0
+var pciAddressRe = regexp.MustCompile(`^[0-9a-f]{4}:[0-9a-f]{2}:[0-9a-f]{2}\.[0-9a-f]$`)
+
+// Returns key of hwmon chip stable across boots, unlike hwmonN numbering.
+// It's PCI address of the closest PCI device the chip belongs to, i.e. NVMe
+// controller or GPU, or name of its device otherwise. Chips without device
+// fall back to directory name
+func hwmonDeviceKey(dir string) string {
+	device, err := filepath.EvalSymlinks(filepath.Join(dir, "device"))
+	if err != nil {
+		return filepath.Base(dir)
+	}
+	for path := device; path != filepath.Dir(path); path = filepath.Dir(path) {
+		if pciAddressRe.MatchString(filepath.Base(path)) {
+			return filepath.Base(path)
+		}
+	}
+	return filepath.Base(device)
+}
+
+func (c *SensorsCollector) discoverHwmon(dir string) {
+	// NOTE: Older kernels keep attributes in the device directory
+	attrDir := dir
+	chip, err := readSysfsString(filepath.Join(attrDir, "name"))
+	if err != nil {
+		attrDir = filepath.Join(dir, "device")
+		chip, err = readSysfsString(filepath.Join(attrDir, "name"))
+		if err != nil {
+			return
+		}
+	}
+
+	files, err := os.ReadDir(attrDir)
+	if err != nil {
+		return
+	}
+	key := hwmonDeviceKey(dir)
+	type hwmonInput struct {
+		file string
+		kind SensorKind
+	}
+	inputs := make(map[string]hwmonInput)
+	for _, file := range files {
+		match := hwmonInputRe.FindStringSubmatch(file.Name())
+		if match == nil {
+			continue
+		}
+		base := match[1] + match[2]
+		// NOTE: Power may have both, instant input is preferred
+		if _, present := inputs[base]; present && match[3] != "input" {
+			continue
+		}
+		kind := SensorKind(slices.IndexFunc(sensorKinds, func(desc sensorKind) bool {
+			return desc.prefix == match[1]
+		}))
+		inputs[base] = hwmonInput{file.Name(), kind}
+	}
+
+	for _, base := range slices.Sorted(maps.Keys(inputs)) {
+		input := inputs[base]
+		attr := func(name string) string {
+			return filepath.Join(attrDir, base+"_"+name)
+		}
+
+		label, err := readSysfsString(attr("label"))
+		if err != nil || label == "" {
+			label = base
+		}
+		sensor := NewSensorStats(c.size, chip, label, input.kind)
+		sensor.inputPath = filepath.Join(attrDir, input.file)
+		sensor.Min = readSensorLimit(attr("min"), sensor.scale)
+		sensor.Max = readSensorLimit(attr("max"), sensor.scale)
+		sensor.Crit = readSensorLimit(attr("crit"), sensor.scale)
+		c.addSensor(sensor, key)
+	}
+}
+
+func (c *SensorsCollector) discoverThermalZone(dir string) {
+	zoneType, err := readSysfsString(filepath.Join(dir, "type"))
+	if err != nil {
+		return
+	}
+
+	sensor := NewSensorStats(c.size, "thermal", zoneType, SensorTemp)
+	sensor.inputPath = filepath.Join(dir, "temp")
+
+	trips, _ := filepath.Glob(filepath.Join(dir, "trip_point_*_type"))
+	for _, trip := range trips {
+		tripType, err := readSysfsString(trip)
+		if err != nil {
+			continue
+		}
+		temp := readSensorLimit(
+			strings.TrimSuffix(trip, "_type")+"_temp", sensor.scale,
+		)
+		if math.IsNaN(temp) {
+			continue
+		}
+		// NOTE: Zone may have several trips of a type, the lowest one
+		// triggers first
+		switch tripType {
+		case "hot":
+			sensor.Max = math.Min(temp, nanToInf(sensor.Max))
+		case "critical":
+			sensor.Crit = math.Min(temp, nanToInf(sensor.Crit))
+		}
+	}
+	c.addSensor(sensor, filepath.Base(dir))
+}
+
+func nanToInf(value float64) float64 {
+	if math.IsNaN(value) {
+		return math.Inf(1)
+	}
+	return value
+}
+
+// Finds hwmon and thermal zone sensors. Missing classes are not an error,
+// i.e. virtual machines often have no sensors at all
+func (c *SensorsCollector) Discover() error {
+	for _, class := range []struct {
+		pattern  string
+		key      func(dir string) string
+		discover func(dir string)
+	}{
+		{"class/hwmon/hwmon*", hwmonDeviceKey, c.discoverHwmon},
+		{"class/thermal/thermal_zone*", filepath.Base, c.discoverThermalZone},
+	} {
+		dirs, err := filepath.Glob(filepath.Join(c.root, class.pattern))
+		if err != nil {
+			return fmt.Errorf("failed to discover sensors: %w", err)
+		}
+		// NOTE: Sorted by key, so the same sensor gets the same name each boot
+		slices.SortStableFunc(dirs, func(a, b string) int {
+			return strings.Compare(class.key(a), class.key(b))
+		})
+		for _, dir := range dirs {
+			class.discover(dir)
+		}
+	}
+	return nil
+}
+
+func (c *SensorsCollector) Collect() error {
+	for _, sensor := range c.Sensors {
+		if !HasActiveEntries(sensor) {
+			continue
+		}
+
+		// NOTE: Chip may be unplugged or suspended, i.e. discrete GPU in
+		// runtime power management, reading fails until it's back
+		value, err := readSysfsFloat(sensor.inputPath)
+		if err != nil {
+			value = math.NaN()
+		}
+
+		MapValues([]valToEntry{
+			{value * sensor.scale, &sensor.Value},
+		})
+	}
+
+	return nil
+}
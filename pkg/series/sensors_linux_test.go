@@ -0,0 +1,219 @@
+package series
+
+import (
+	"maps"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Creates files under root, keys are paths relative to it
+func writeSysfsTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content+"\n"), 0o644))
+	}
+}
+
+func TestSensorsCollector(t *testing.T) {
+	root := t.TempDir()
+	writeSysfsTree(t, root, map[string]string{
+		"class/hwmon/hwmon0/name":        "coretemp",
+		"class/hwmon/hwmon0/temp1_input": "52000",
+		"class/hwmon/hwmon0/temp1_label": "Package id 0",
+		"class/hwmon/hwmon0/temp1_max":   "80000",
+		"class/hwmon/hwmon0/temp1_crit":  "100000",
+
+		"class/hwmon/hwmon1/name":        "nct6775",
+		"class/hwmon/hwmon1/fan1_input":  "1200",
+		"class/hwmon/hwmon1/fan1_min":    "300",
+		"class/hwmon/hwmon1/fan2_input":  "0",
+		"class/hwmon/hwmon1/fan2_min":    "0",
+		"class/hwmon/hwmon1/in0_input":   "1104",
+		"class/hwmon/hwmon1/in0_max":     "1744",
+		"class/hwmon/hwmon1/in0_label":   "Vcore",
+		"class/hwmon/hwmon1/temp1_input": "41000",
+
+		"class/hwmon/hwmon2/name":           "amdgpu",
+		"class/hwmon/hwmon2/power1_average": "25000000",
+
+		"class/hwmon/hwmon3/name":        "nvme",
+		"class/hwmon/hwmon3/temp1_input": "38850",
+		"class/hwmon/hwmon3/temp1_label": "Composite",
+		"class/hwmon/hwmon4/name":        "nvme",
+		"class/hwmon/hwmon4/temp1_input": "44850",
+		"class/hwmon/hwmon4/temp1_label": "Composite",
+
+		// NOTE: Layout of older kernels
+		"class/hwmon/hwmon5/device/name":        "it87",
+		"class/hwmon/hwmon5/device/temp2_input": "30000",
+
+		// NOTE: Not a chip, skipped
+		"class/hwmon/hwmon6/uevent": "",
+
+		"class/thermal/thermal_zone0/type":              "x86_pkg_temp",
+		"class/thermal/thermal_zone0/temp":              "53000",
+		"class/thermal/thermal_zone0/trip_point_0_type": "passive",
+		"class/thermal/thermal_zone0/trip_point_0_temp": "70000",
+		"class/thermal/thermal_zone0/trip_point_1_type": "hot",
+		"class/thermal/thermal_zone0/trip_point_1_temp": "95000",
+		"class/thermal/thermal_zone0/trip_point_2_type": "critical",
+		"class/thermal/thermal_zone0/trip_point_2_temp": "105000",
+	})
+
+	c := NewSensorsCollector(root, 2)
+	require.NoError(t, c.Discover())
+
+	nan := math.NaN()
+	tests := []struct {
+		name           string
+		kind           SensorKind
+		min, max, crit float64
+		value          float64
+	}{
+		{"coretemp Package id 0", SensorTemp, nan, 80, 100, 52},
+		{"nct6775 fan1", SensorFan, 300, nan, nan, 1200},
+		{"nct6775 fan2", SensorFan, nan, nan, nan, 0},
+		{"nct6775 Vcore", SensorVoltage, nan, 1.744, nan, 1.104},
+		{"nct6775 temp1", SensorTemp, nan, nan, nan, 41},
+		{"amdgpu power1", SensorPower, nan, nan, nan, 25},
+		{"nvme Composite", SensorTemp, nan, nan, nan, 38.85},
+		{"nvme Composite (hwmon4)", SensorTemp, nan, nan, nan, 44.85},
+		{"it87 temp2", SensorTemp, nan, nan, nan, 30},
+		{"thermal x86_pkg_temp", SensorTemp, nan, 95, 105, 53},
+	}
+
+	names := make([]string, len(tests))
+	for x, tt := range tests {
+		names[x] = tt.name
+	}
+	require.ElementsMatch(t, names, slices.Collect(maps.Keys(c.Sensors)))
+
+	sub := &Subscriber{}
+	for _, sensor := range c.Sensors {
+		sensor.Value.Subscribe(sub)
+	}
+	require.NoError(t, c.Collect())
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sensor := c.Sensors[tt.name]
+			assert.Equal(t, tt.kind, sensor.Kind)
+			for _, limit := range []struct{ got, want float64 }{
+				{sensor.Min, tt.min},
+				{sensor.Max, tt.max},
+				{sensor.Crit, tt.crit},
+			} {
+				if math.IsNaN(limit.want) {
+					assert.True(t, math.IsNaN(limit.got))
+				} else {
+					assert.InDelta(t, limit.want, limit.got, 1e-9)
+				}
+			}
+			assert.InDelta(t, tt.value, sensor.Value.GetData().GetFirstValue(), 1e-9)
+		})
+	}
+
+	t.Run("Discover again", func(t *testing.T) {
+		require.NoError(t, c.Discover())
+		assert.Len(t, c.Sensors, len(tests))
+	})
+
+	t.Run("Unplugged", func(t *testing.T) {
+		require.NoError(t, os.RemoveAll(filepath.Join(root, "class/hwmon/hwmon2")))
+		require.NoError(t, c.Collect())
+		assert.True(t, math.IsNaN(c.Sensors["amdgpu power1"].Value.GetData().GetFirstValue()))
+		assert.InDelta(t, 52, c.Sensors["coretemp Package id 0"].Value.GetData().GetFirstValue(), 1e-9)
+	})
+}
+
+func TestSensorsCollector_NoSensors(t *testing.T) {
+	c := NewSensorsCollector(t.TempDir(), 2)
+	require.NoError(t, c.Discover())
+	assert.Empty(t, c.Sensors)
+	require.NoError(t, c.Collect())
+}
+
+func TestSensorsCollector_SameChips(t *testing.T) {
+	// NOTE: hwmon numbering follows probe order, which may change each boot
+	for _, order := range [][2]string{{"hwmon0", "hwmon1"}, {"hwmon1", "hwmon0"}} {
+		t.Run(order[0]+" first", func(t *testing.T) {
+			root := t.TempDir()
+			drives := []struct {
+				hwmon, pci, temp string
+			}{
+				{order[0], "0000:01:00.0", "38850"},
+				{order[1], "0000:02:00.0", "44850"},
+			}
+			for _, drive := range drives {
+				device := filepath.Join("devices/pci0000:00", drive.pci, "nvme/nvme0")
+				writeSysfsTree(t, root, map[string]string{
+					device + "/dev": "259:0",
+
+					"class/hwmon/" + drive.hwmon + "/name":        "nvme",
+					"class/hwmon/" + drive.hwmon + "/temp1_input": drive.temp,
+					"class/hwmon/" + drive.hwmon + "/temp1_label": "Composite",
+				})
+				require.NoError(t, os.Symlink(
+					filepath.Join(root, device),
+					filepath.Join(root, "class/hwmon", drive.hwmon, "device"),
+				))
+			}
+
+			c := NewSensorsCollector(root, 2)
+			require.NoError(t, c.Discover())
+			require.ElementsMatch(t,
+				[]string{"nvme Composite", "nvme Composite (0000:02:00.0)"},
+				slices.Collect(maps.Keys(c.Sensors)),
+			)
+			sub := &Subscriber{}
+			for _, sensor := range c.Sensors {
+				sensor.Value.Subscribe(sub)
+			}
+			require.NoError(t, c.Collect())
+			assert.InDelta(t, 38.85, c.Sensors["nvme Composite"].Value.GetData().GetFirstValue(), 1e-9)
+			assert.InDelta(t, 44.85, c.Sensors["nvme Composite (0000:02:00.0)"].Value.GetData().GetFirstValue(), 1e-9)
+		})
+	}
+}
+
+func TestHwmonDeviceKey(t *testing.T) {
+	root := t.TempDir()
+	writeSysfsTree(t, root, map[string]string{
+		"devices/pci0000:00/0000:00:1d.0/0000:3d:00.0/nvme/nvme0/dev": "259:0",
+		"devices/platform/coretemp.0/uevent":                          "",
+		"class/hwmon/hwmon0/name":                                     "nvme",
+		"class/hwmon/hwmon1/name":                                     "coretemp",
+		"class/hwmon/hwmon2/name":                                     "acpitz",
+	})
+	for hwmon, device := range map[string]string{
+		"hwmon0": "devices/pci0000:00/0000:00:1d.0/0000:3d:00.0/nvme/nvme0",
+		"hwmon1": "devices/platform/coretemp.0",
+	} {
+		require.NoError(t, os.Symlink(
+			filepath.Join(root, device), filepath.Join(root, "class/hwmon", hwmon, "device"),
+		))
+	}
+
+	tests := []struct {
+		hwmon string
+		want  string
+	}{
+		{"hwmon0", "0000:3d:00.0"},
+		{"hwmon1", "coretemp.0"},
+		// NOTE: Virtual chip without device
+		{"hwmon2", "hwmon2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.hwmon, func(t *testing.T) {
+			assert.Equal(t, tt.want, hwmonDeviceKey(filepath.Join(root, "class/hwmon", tt.hwmon)))
+		})
+	}
+}
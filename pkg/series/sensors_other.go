@@ -0,0 +1,17 @@
+//go:build !linux
+
+package series
+
+// NOTE: Sensors are not implemented for this platform, the types exist so
+// stats compile everywhere
+type SensorStats struct {
+	Name string
+
+	Value Entry `json:"value"`
+}
+
+type SensorsCollector struct {
+	Collector
+
+	Sensors map[string]*SensorStats
+}
@@ -1,9 +1,11 @@
 package series
 
 import (
+	"math"
 	"reflect"
 	"strings"
 	"sync"
+	"time"
 
 	"n4/gui-test/pkg/tickstore"
 )
@@ -33,6 +35,16 @@ type Entry struct {
 
 	size int
 	data *EntryData
+	// Time between samples, set by scheduler of the collector
+	interval time.Duration
+	// Time of the latest value that is not a gap
+	updated time.Time
+	// Data starts with gaps instead of zeros
+	gapFilled bool
+	// Data got values since it was created
+	hasValues bool
+	// Values added since entry was created, including gaps
+	samples uint64
 
 	subscribers SubscribersMap
 }
@@ -45,12 +57,102 @@ func NewEntry(size int) *Entry {
 	return &entry
 }
 
+// Returns entry whose data starts with gaps instead of zeros, for values
+// where zero means something, i.e. time until disk is full
+func NewGapEntry(size int) *Entry {
+	entry := NewEntry(size)
+	entry.gapFilled = true
+	return entry
+}
+
+// NOTE: Must be called with lock held
+func (e *Entry) resetData() {
+	e.data = NewEntryData(e.size)
+	e.hasValues = false
+	if e.gapFilled {
+		values := e.data.GetValues()
+		for x := range values {
+			values[x] = math.NaN()
+		}
+	}
+}
+
 func (e *Entry) GetData() *EntryData {
 	e.lock.Lock()
 	defer e.lock.Unlock()
 	return e.data
 }
 
+// Returns the latest value, NaN if entry got no values since it was
+// subscribed
+func (e *Entry) GetLatestValue() float64 {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if e.data == nil || !e.hasValues {
+		return math.NaN()
+	}
+	return e.data.GetFirstValue()
+}
+
+// Returns number of values added since entry was created, so readers of data
+// can tell how many of them are new
+func (e *Entry) GetSamples() uint64 {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	return e.samples
+}
+
+// Returns time between samples, zero if unknown
+func (e *Entry) GetInterval() time.Duration {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	return e.interval
+}
+
+func (e *Entry) SetInterval(interval time.Duration) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.interval = interval
+}
+
+// Number of sample intervals without values after which entry is stale
+const staleIntervals = 3
+
+// Returns true if entry has been collected, but got no values for a few
+// sample intervals, i.e. its collector hangs or backs off
+func (e *Entry) IsStale(now time.Time) bool {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if e.interval <= 0 || e.updated.IsZero() || len(e.subscribers) == 0 {
+		return false
+	}
+	return now.Sub(e.updated) > staleIntervals*e.interval
+}
+
+// Adds value if entry is active. Safe to call concurrently with other
+// methods
+func (e *Entry) AddValue(val float64) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.addValue(val)
+}
+
+// NOTE: Must be called with lock held
+func (e *Entry) addValue(val float64) {
+	if len(e.subscribers) == 0 {
+		return
+	}
+	if e.data == nil {
+		e.resetData()
+	}
+	e.data.AddValues(val)
+	e.hasValues = true
+	e.samples++
+	if !math.IsNaN(val) {
+		e.updated = time.Now()
+	}
+}
+
 func (e *Entry) Subscribe(sub *Subscriber) *EntryData {
 	e.lock.Lock()
 	defer e.lock.Unlock()
@@ -61,7 +163,7 @@ func (e *Entry) Subscribe(sub *Subscriber) *EntryData {
 	}
 	e.subscribers[sub] = struct{}{}
 	if len(e.subscribers) == 1 {
-		e.data = NewEntryData(e.size)
+		e.resetData()
 	}
 	return e.data
 }
@@ -76,7 +178,7 @@ func (e *Entry) Unsubscribe(sub *Subscriber) {
 	}
 	delete(e.subscribers, sub)
 	if len(e.subscribers) == 0 {
-		e.data = NewEntryData(e.size)
+		e.resetData()
 	}
 }
 
@@ -87,8 +189,28 @@ func (e *Entry) IsActive() bool {
 	return len(e.subscribers) > 0
 }
 
+// Returns name usable as a part of entry and config names: lowercase
+// letters, digits and underscores
+func NamePart(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r - 'A' + 'a'
+		}
+		return '_'
+	}, name)
+}
+
 // TODO: Benchmark GetEntries
 func GetEntries[T any](structPtr *T, prefixes ...string) (entries map[string]*Entry) {
+	return EntriesOf(structPtr, prefixes...)
+}
+
+// Returns entries of struct pointed by structPtr by their json names joined
+// with prefixes, i.e. of collector held in an interface
+func EntriesOf(structPtr any, prefixes ...string) (entries map[string]*Entry) {
 	prefix := strings.Join(prefixes, "_")
 	entries = make(map[string]*Entry)
 	entryType := reflect.TypeOf(Entry{})
@@ -137,34 +259,96 @@ func HasActiveEntries[T any](structPtr *T) bool {
 	return false
 }
 
-type valToEntry struct {
-	val   float64
-	entry *Entry
+var entryPtrType = reflect.TypeOf((*Entry)(nil))
+
+// Sets sample interval of every entry reachable from exported fields of
+// collector, including nested stats in pointers, slices and maps
+func SetEntriesInterval(collector any, interval time.Duration) {
+	if c, ok := collector.(interface{ setInterval(time.Duration) }); ok {
+		c.setInterval(interval)
+	}
+	walkEntries(reflect.ValueOf(collector), func(entry *Entry) {
+		entry.SetInterval(interval)
+	})
 }
 
-func mapValues(
-	valToEntryMapping []valToEntry,
-	valComp func(val float64, entry *Entry) float64,
-) {
-	for _, mapping := range valToEntryMapping {
-		if !mapping.entry.IsActive() {
-			continue
+// Records a gap in every active entry of collector, i.e. when collection
+// timed out
+func AddGaps(collector any) {
+	walkEntries(reflect.ValueOf(collector), func(entry *Entry) {
+		entry.AddValue(math.NaN())
+	})
+}
+
+// Collector guarding its stats, walks entries itself
+type entriesWalker interface {
+	walkEntries(fn func(entry *Entry))
+}
+
+// Calls fn for entries reachable from exported fields of val
+func walkEntries(val reflect.Value, fn func(entry *Entry)) {
+	if val.Kind() == reflect.Pointer && !val.IsNil() && val.CanInterface() {
+		if walker, ok := val.Interface().(entriesWalker); ok {
+			walker.walkEntries(fn)
+			return
 		}
-		if mapping.entry.data == nil {
-			mapping.entry.data = NewEntryData(mapping.entry.size)
+	}
+	switch val.Kind() {
+	case reflect.Pointer:
+		if val.IsNil() {
+			return
+		}
+		if val.Type() == entryPtrType {
+			fn(val.Interface().(*Entry))
+			return
+		}
+		walkEntries(val.Elem(), fn)
+	case reflect.Interface:
+		if !val.IsNil() {
+			walkEntries(val.Elem(), fn)
+		}
+	case reflect.Struct:
+		// NOTE: Only stats of this package are walked, values of maps are not
+		// addressable and are skipped too
+		if !val.CanAddr() || val.Type().PkgPath() != entryPtrType.Elem().PkgPath() {
+			return
+		}
+		if val.Type() == entryPtrType.Elem() {
+			walkEntries(val.Addr(), fn)
+			return
+		}
+		for x := 0; x < val.NumField(); x++ {
+			if val.Type().Field(x).IsExported() {
+				walkEntries(val.Field(x), fn)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for x := 0; x < val.Len(); x++ {
+			walkEntries(val.Index(x), fn)
+		}
+	case reflect.Map:
+		iter := val.MapRange()
+		for iter.Next() {
+			walkEntries(iter.Value(), fn)
 		}
-		mapping.entry.data.AddValues(valComp(mapping.val, mapping.entry))
 	}
 }
 
-func MapValues(valToEntryMapping []valToEntry) {
-	mapValues(valToEntryMapping, func(val float64, _ *Entry) float64 {
-		return val
-	})
+type valToEntry struct {
+	val   float64
+	entry *Entry
 }
 
-func MapValuesWithDiff(valToEntryMapping []valToEntry) {
-	mapValues(valToEntryMapping, func(val float64, entry *Entry) float64 {
-		return val - entry.data.GetFirstValue()
-	})
+func MapValues(valToEntryMapping []valToEntry) {
+	for _, mapping := range valToEntryMapping {
+		entry := mapping.entry
+		entry.lock.Lock()
+		if len(entry.subscribers) > 0 {
+			if entry.data == nil {
+				entry.resetData()
+			}
+			entry.addValue(mapping.val)
+		}
+		entry.lock.Unlock()
+	}
 }
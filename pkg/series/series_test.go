@@ -2,7 +2,9 @@ package series
 
 import (
 	"fmt"
+	"math"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -45,6 +47,22 @@ func TestNewEntry(t *testing.T) {
 	}
 }
 
+func TestNewGapEntry(t *testing.T) {
+	entry := NewGapEntry(3)
+	sub := &Subscriber{}
+	data := entry.Subscribe(sub)
+	for _, val := range data.GetValues() {
+		assert.True(t, math.IsNaN(val))
+	}
+
+	entry.AddValue(0)
+	assert.Equal(t, 0.0, entry.GetData().GetFirstValue())
+
+	// Data is reset to gaps too
+	entry.Unsubscribe(sub)
+	assert.True(t, math.IsNaN(entry.Subscribe(sub).GetFirstValue()))
+}
+
 func TestEntry_Subscriber_SamePtr(t *testing.T) {
 	sub1 := &Subscriber{}
 	sub2 := &Subscriber{}
@@ -96,3 +114,71 @@ func TestEntry_Subscribe_Unsubscribe(t *testing.T) {
 	assert.Equal(t, SubscribersMap{}, entry.subscribers)
 	assert.False(t, entry.IsActive())
 }
+
+func TestSetEntriesInterval(t *testing.T) {
+	type nestedStats struct {
+		Value Entry `json:"value"`
+	}
+	type testCollector struct {
+		Collector
+
+		Total  Entry `json:"total"`
+		hidden Entry
+
+		Nested []*nestedStats
+		Named  map[string]*nestedStats
+		Absent *nestedStats
+	}
+	c := &testCollector{
+		Collector: Collector{size: 1},
+
+		Total:  *NewEntry(1),
+		hidden: *NewEntry(1),
+
+		Nested: []*nestedStats{{Value: *NewEntry(1)}},
+		Named:  map[string]*nestedStats{"a": {Value: *NewEntry(1)}},
+	}
+
+	SetEntriesInterval(c, 250*time.Millisecond)
+
+	assert.Equal(t, 250*time.Millisecond, c.Total.GetInterval())
+	assert.Equal(t, 250*time.Millisecond, c.Nested[0].Value.GetInterval())
+	assert.Equal(t, 250*time.Millisecond, c.Named["a"].Value.GetInterval())
+	assert.Zero(t, c.hidden.GetInterval())
+}
+
+func TestEntry_AddValue_IsStale(t *testing.T) {
+	entry := NewEntry(2)
+	entry.SetInterval(time.Second)
+
+	entry.AddValue(1)
+	assert.Nil(t, entry.GetData(), "inactive entry must not collect")
+	assert.Zero(t, entry.GetSamples())
+
+	sub := &Subscriber{}
+	entry.Subscribe(sub)
+	now := time.Now()
+	assert.False(t, entry.IsStale(now), "never updated entry is not stale")
+
+	entry.AddValue(1)
+	entry.AddValue(math.NaN())
+	assert.True(t, math.IsNaN(entry.GetData().GetFirstValue()))
+	assert.Equal(t, uint64(2), entry.GetSamples(), "gaps are samples too")
+	assert.False(t, entry.IsStale(time.Now()))
+	assert.True(t, entry.IsStale(time.Now().Add(4*time.Second)))
+}
+
+func TestAddGaps(t *testing.T) {
+	c := &SysMemCollector{
+		Collector: Collector{size: 2},
+		Total:     *NewEntry(2),
+		Used:      *NewEntry(2),
+	}
+	sub := &Subscriber{}
+	c.Total.Subscribe(sub)
+
+	AddGaps(c)
+
+	assert.True(t, math.IsNaN(c.Total.GetData().GetFirstValue()))
+	assert.Nil(t, c.Used.GetData())
+}
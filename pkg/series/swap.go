@@ -0,0 +1,127 @@
+package series
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/mem"
+)
+
+const vmstatPath = "/proc/vmstat"
+
+// Swap usage and paging activity. Rates are calculated from /proc/vmstat
+// counters and are NaN where it is not available
+type SwapCollector struct {
+	Collector
+
+	vmstatPath string
+	pageSize   float64
+
+	counters *Counters
+
+	Total       Entry `json:"total"`
+	Used        Entry `json:"used"`
+	UsedPercent Entry `json:"used_percent"`
+
+	// Bytes per second swapped in and out
+	SwapIn  Entry `json:"swap_in"`
+	SwapOut Entry `json:"swap_out"`
+
+	// Bytes per second paged in from and out to disk, including files
+	PageIn  Entry `json:"page_in"`
+	PageOut Entry `json:"page_out"`
+
+	// Page faults per second that required reading from disk
+	MajorFaults Entry `json:"major_faults"`
+}
+
+func NewSwapCollector(size int) *SwapCollector {
+	if size < 1 {
+		panic("size must be greater than zero")
+	}
+	collector := SwapCollector{
+		Collector: Collector{size: size},
+
+		vmstatPath: vmstatPath,
+		pageSize:   float64(os.Getpagesize()),
+		counters:   NewCounters(),
+
+		Total:       *NewEntry(size),
+		Used:        *NewEntry(size),
+		UsedPercent: *NewEntry(size),
+
+		SwapIn:  *NewEntry(size),
+		SwapOut: *NewEntry(size),
+
+		PageIn:  *NewEntry(size),
+		PageOut: *NewEntry(size),
+
+		MajorFaults: *NewEntry(size),
+	}
+	return &collector
+}
+
+// Returns vmstat counters, nil if vmstat is not available on this platform
+func (c *SwapCollector) readVmstat() (map[string]uint64, error) {
+	file, err := os.Open(c.vmstatPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get paging stats: %w", err)
+	}
+	defer file.Close()
+	return parseVmstat(file)
+}
+
+// Returns vmstat counter, NaN if it's missing
+func vmstatValue(vmstat map[string]uint64, name string) float64 {
+	val, present := vmstat[name]
+	if !present {
+		return math.NaN()
+	}
+	return float64(val)
+}
+
+func (c *SwapCollector) collect(swap *mem.SwapMemoryStat, now time.Time) error {
+	vmstat, err := c.readVmstat()
+	if err != nil {
+		return err
+	}
+
+	MapValues([]valToEntry{
+		{float64(swap.Total), &c.Total},
+		{float64(swap.Used), &c.Used},
+		{swap.UsedPercent, &c.UsedPercent},
+	})
+	c.counters.MapRates([]valToEntry{
+		{vmstatValue(vmstat, "pswpin"), &c.SwapIn},
+		{vmstatValue(vmstat, "pswpout"), &c.SwapOut},
+	}, now, c.pageSize)
+	// NOTE: pgpgin and pgpgout are counted in KiB
+	c.counters.MapRates([]valToEntry{
+		{vmstatValue(vmstat, "pgpgin"), &c.PageIn},
+		{vmstatValue(vmstat, "pgpgout"), &c.PageOut},
+	}, now, 1024)
+	c.counters.MapRates([]valToEntry{
+		{vmstatValue(vmstat, "pgmajfault"), &c.MajorFaults},
+	}, now, 1)
+
+	return nil
+}
+
+func (c *SwapCollector) Collect() error {
+	if !HasActiveEntries(c) {
+		return nil
+	}
+
+	swap, err := mem.SwapMemory()
+	if err != nil {
+		return fmt.Errorf("failed to get swap stats: %w", err)
+	}
+	return c.collect(swap, time.Now())
+}
@@ -0,0 +1,79 @@
+package series
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/mem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVmstat(t *testing.T) {
+	file, err := os.Open("testdata/vmstat")
+	require.NoError(t, err)
+	defer file.Close()
+
+	vmstat, err := parseVmstat(file)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(21583), vmstat["pswpin"])
+	assert.Equal(t, uint64(43871), vmstat["pswpout"])
+	assert.Equal(t, uint64(91238), vmstat["pgmajfault"])
+}
+
+func TestSwapCollector_Collect(t *testing.T) {
+	data, err := os.ReadFile("testdata/vmstat")
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "vmstat")
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+
+	c := NewSwapCollector(2)
+	c.vmstatPath = path
+	c.pageSize = 4096
+	sub := &Subscriber{}
+	for _, entry := range GetEntries(c) {
+		entry.Subscribe(sub)
+	}
+
+	swap := &mem.SwapMemoryStat{Total: 1000, Used: 250, UsedPercent: 25}
+	now := time.Now()
+	require.NoError(t, c.collect(swap, now))
+	assert.Equal(t, 250.0, c.Used.GetData().GetFirstValue())
+	assert.True(t, math.IsNaN(c.SwapIn.GetData().GetFirstValue()),
+		"rate of the first sample is unknown")
+
+	require.NoError(t, os.WriteFile(path, []byte(
+		"pgpgin 18349384\npgpgout 30125560\npswpin 21603\npswpout 43871\n"+
+			"pgmajfault 91138\n",
+	), 0o644))
+	require.NoError(t, c.collect(swap, now.Add(2*time.Second)))
+
+	tests := []struct {
+		name  string
+		entry *Entry
+		want  float64
+	}{
+		{"swap in", &c.SwapIn, 20 * 4096 / 2},
+		{"swap out", &c.SwapOut, 0},
+		{"page in", &c.PageIn, 100 * 1024 / 2},
+		{"page out", &c.PageOut, 0},
+		{"counter reset", &c.MajorFaults, math.NaN()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.entry.GetData().GetFirstValue()
+			if math.IsNaN(tt.want) {
+				assert.True(t, math.IsNaN(got))
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	c.vmstatPath = filepath.Join(t.TempDir(), "missing")
+	require.NoError(t, c.collect(swap, now.Add(3*time.Second)))
+	assert.True(t, math.IsNaN(c.SwapIn.GetData().GetFirstValue()))
+}
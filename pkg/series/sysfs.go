@@ -0,0 +1,40 @@
+package series
+
+import (
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Default mountpoint of sysfs, collectors reading it accept another root to
+// be tested against a fake tree
+const SysfsRoot = "/sys"
+
+// Returns content of sysfs attribute without trailing newline
+func readSysfsString(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// Returns numeric value of sysfs attribute
+func readSysfsFloat(path string) (float64, error) {
+	value, err := readSysfsString(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+// Returns numeric value of sysfs attribute multiplied by scale, NaN if it
+// can't be read
+func readSysfsScaled(path string, scale float64) float64 {
+	value, err := readSysfsFloat(path)
+	if err != nil {
+		return math.NaN()
+	}
+	return value * scale
+}
@@ -2,6 +2,7 @@ package series
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/shirou/gopsutil/v4/mem"
 )
@@ -13,6 +14,11 @@ type SysMemCollector struct {
 	Available   Entry `json:"available"`
 	Used        Entry `json:"used"`
 	UsedPercent Entry `json:"used_percent"`
+
+	// Seconds until available memory runs out at its trend, +Inf if it
+	// doesn't fall
+	TimeToFull Entry `json:"time_to_full"`
+	trend      *TrendEstimator
 }
 
 func NewSysMemCollector(size int) *SysMemCollector {
@@ -26,6 +32,9 @@ func NewSysMemCollector(size int) *SysMemCollector {
 		Available:   *NewEntry(size),
 		Used:        *NewEntry(size),
 		UsedPercent: *NewEntry(size),
+
+		TimeToFull: *NewGapEntry(size),
+		trend:      NewTrendEstimator(DefaultTrendWindow),
 	}
 	return &collector
 }
@@ -40,11 +49,15 @@ func (c *SysMemCollector) Collect() error {
 		return fmt.Errorf("failed to get memory stats: %w", err)
 	}
 
+	c.trend.Add(time.Now(), float64(sysMem.Available))
+
 	MapValues([]valToEntry{
 		{float64(sysMem.Total), &c.Total},
 		{float64(sysMem.Available), &c.Available},
 		{float64(sysMem.Used), &c.Used},
 		{float64(sysMem.UsedPercent), &c.UsedPercent},
+
+		{c.trend.TimeToZero(), &c.TimeToFull},
 	})
 
 	return nil
@@ -0,0 +1,150 @@
+package series
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	meminfoPath    = "/proc/meminfo"
+	overcommitPath = "/proc/sys/vm/overcommit_memory"
+
+	// vm.overcommit_memory mode enforcing CommitLimit
+	overcommitNever = 2
+)
+
+type SysMemExCollector struct {
+	Collector
+
+	meminfoPath    string
+	overcommitPath string
+
+	CommitLimit Entry `json:"commit_limit"`
+	CommitTotal Entry `json:"commit_total"`
+	// Seconds until commit charge reaches the limit at its trend, +Inf if
+	// it doesn't grow. NaN unless overcommit is disabled, otherwise the limit
+	// is not enforced and commit charge is routinely above it
+	CommitTimeToFull Entry `json:"commit_time_to_full"`
+	commitTrend      *TrendEstimator
+
+	Dirty     Entry `json:"dirty"`
+	Writeback Entry `json:"writeback"`
+
+	Slab            Entry `json:"slab"`
+	SlabReclaimable Entry `json:"slab_reclaimable"`
+
+	// Page cache
+	Cached  Entry `json:"cached"`
+	Buffers Entry `json:"buffers"`
+
+	HugePagesTotal Entry `json:"huge_pages_total"`
+	HugePagesUsed  Entry `json:"huge_pages_used"`
+}
+
+func NewSysMemExCollector(size int) *SysMemExCollector {
+	if size < 1 {
+		panic("size must be greater than zero")
+	}
+	collector := SysMemExCollector{
+		Collector: Collector{size: size},
+
+		meminfoPath:    meminfoPath,
+		overcommitPath: overcommitPath,
+
+		CommitLimit: *NewEntry(size),
+		CommitTotal: *NewEntry(size),
+
+		CommitTimeToFull: *NewGapEntry(size),
+		commitTrend:      NewTrendEstimator(DefaultTrendWindow),
+
+		Dirty:     *NewEntry(size),
+		Writeback: *NewEntry(size),
+
+		Slab:            *NewEntry(size),
+		SlabReclaimable: *NewEntry(size),
+
+		Cached:  *NewEntry(size),
+		Buffers: *NewEntry(size),
+
+		HugePagesTotal: *NewEntry(size),
+		HugePagesUsed:  *NewEntry(size),
+	}
+	return &collector
+}
+
+// Returns true if kernel enforces CommitLimit, false if the setting is missing
+func (c *SysMemExCollector) isCommitLimited() (bool, error) {
+	data, err := os.ReadFile(c.overcommitPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get overcommit mode: %w", err)
+	}
+	mode, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return false, fmt.Errorf("failed to parse overcommit mode: %w", err)
+	}
+	return mode == overcommitNever, nil
+}
+
+func (c *SysMemExCollector) Collect() error {
+	if !HasActiveEntries(c) {
+		return nil
+	}
+
+	file, err := os.Open(c.meminfoPath)
+	if err != nil {
+		return fmt.Errorf("failed to get memory stats: %w", err)
+	}
+	defer file.Close()
+	meminfo, err := parseMeminfo(file)
+	if err != nil {
+		return fmt.Errorf("failed to get memory stats: %w", err)
+	}
+
+	hugePageSize := meminfo["Hugepagesize"]
+	hugePagesTotal := meminfo["HugePages_Total"]
+	hugePagesFree := min(meminfo["HugePages_Free"], hugePagesTotal)
+
+	commitTimeToFull := math.NaN()
+	if c.CommitTimeToFull.IsActive() {
+		limited, err := c.isCommitLimited()
+		if err != nil {
+			return err
+		}
+		if limited {
+			c.commitTrend.Add(
+				time.Now(),
+				float64(meminfo["CommitLimit"])-float64(meminfo["Committed_AS"]),
+			)
+			commitTimeToFull = c.commitTrend.TimeToZero()
+		}
+	}
+
+	MapValues([]valToEntry{
+		{float64(meminfo["CommitLimit"]), &c.CommitLimit},
+		{float64(meminfo["Committed_AS"]), &c.CommitTotal},
+		{commitTimeToFull, &c.CommitTimeToFull},
+
+		{float64(meminfo["Dirty"]), &c.Dirty},
+		{float64(meminfo["Writeback"]), &c.Writeback},
+
+		{float64(meminfo["Slab"]), &c.Slab},
+		{float64(meminfo["SReclaimable"]), &c.SlabReclaimable},
+
+		{float64(meminfo["Cached"]), &c.Cached},
+		{float64(meminfo["Buffers"]), &c.Buffers},
+
+		{float64(hugePagesTotal * hugePageSize), &c.HugePagesTotal},
+		{float64((hugePagesTotal - hugePagesFree) * hugePageSize), &c.HugePagesUsed},
+	})
+
+	return nil
+}
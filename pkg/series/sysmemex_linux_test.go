@@ -0,0 +1,62 @@
+package series
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSysMemExCollector_Collect(t *testing.T) {
+	c := NewSysMemExCollector(2)
+	c.meminfoPath = "testdata/meminfo"
+	c.overcommitPath = "testdata/overcommit/heuristic"
+
+	sub := &Subscriber{}
+	entries := GetEntries(c)
+	for _, entry := range entries {
+		entry.Subscribe(sub)
+	}
+	require.NoError(t, c.Collect())
+
+	tests := []struct {
+		entry *Entry
+		want  float64
+	}{
+		{&c.CommitLimit, 16547808 * 1024},
+		{&c.CommitTotal, 18226316 * 1024},
+		{&c.Dirty, 2616 * 1024},
+		{&c.Writeback, 128 * 1024},
+		{&c.Slab, 782312 * 1024},
+		{&c.SlabReclaimable, 515968 * 1024},
+		{&c.Cached, 7612004 * 1024},
+		{&c.Buffers, 412872 * 1024},
+		{&c.HugePagesTotal, 64 * 2048 * 1024},
+		{&c.HugePagesUsed, 16 * 2048 * 1024},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.entry.GetData().GetFirstValue())
+	}
+}
+
+func TestSysMemExCollector_Collect_CommitTimeToFull(t *testing.T) {
+	c := NewSysMemExCollector(2)
+	// NOTE: Committed_AS is above CommitLimit, as usual with overcommit
+	c.meminfoPath = "testdata/meminfo"
+	c.CommitTimeToFull.Subscribe(&Subscriber{})
+
+	// Limit is not enforced, so commit charge is never "full"
+	c.overcommitPath = "testdata/overcommit/heuristic"
+	require.NoError(t, c.Collect())
+	assert.True(t, math.IsNaN(c.CommitTimeToFull.GetLatestValue()))
+	assert.True(t, math.IsNaN(c.commitTrend.latest))
+
+	c.overcommitPath = "testdata/missing"
+	require.NoError(t, c.Collect())
+	assert.True(t, math.IsNaN(c.CommitTimeToFull.GetLatestValue()))
+
+	c.overcommitPath = "testdata/overcommit/strict"
+	require.NoError(t, c.Collect())
+	assert.Equal(t, float64(16547808-18226316)*1024, c.commitTrend.latest)
+}
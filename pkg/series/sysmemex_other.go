@@ -0,0 +1,9 @@
+//go:build !windows && !linux
+
+package series
+
+// NOTE: Extended memory stats are not implemented for this platform, the
+// type exists so stats compile everywhere
+type SysMemExCollector struct {
+	Collector
+}
@@ -2,6 +2,7 @@ package series
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/shirou/gopsutil/v4/mem"
 )
@@ -11,6 +12,11 @@ type SysMemExCollector struct {
 
 	CommitLimit Entry `json:"commit_limit"`
 	CommitTotal Entry `json:"commit_total"`
+
+	// Seconds until commit charge reaches the limit at its trend, +Inf if
+	// it doesn't grow
+	CommitTimeToFull Entry `json:"commit_time_to_full"`
+	commitTrend      *TrendEstimator
 }
 
 func NewSysMemExCollector(size int) *SysMemExCollector {
@@ -22,6 +28,9 @@ func NewSysMemExCollector(size int) *SysMemExCollector {
 
 		CommitLimit: *NewEntry(size),
 		CommitTotal: *NewEntry(size),
+
+		CommitTimeToFull: *NewGapEntry(size),
+		commitTrend:      NewTrendEstimator(DefaultTrendWindow),
 	}
 	return &collector
 }
@@ -36,9 +45,15 @@ func (c *SysMemExCollector) Collect() error {
 		return fmt.Errorf("failed to get memory stats: %w", err)
 	}
 
+	c.commitTrend.Add(
+		time.Now(), float64(sysMem.CommitLimit)-float64(sysMem.CommitTotal),
+	)
+
 	MapValues([]valToEntry{
 		{float64(sysMem.CommitLimit), &c.CommitLimit},
 		{float64(sysMem.CommitTotal), &c.CommitTotal},
+
+		{c.commitTrend.TimeToZero(), &c.CommitTimeToFull},
 	})
 
 	return nil
@@ -0,0 +1,230 @@
+package series
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// Metric processes are ranked by
+type TopMetric int
+
+const (
+	TopByCPU TopMetric = iota
+	TopByRSS
+	TopByIO
+
+	topMetricsNum
+)
+
+var topMetricNames = []string{
+	TopByCPU: "CPU",
+	TopByRSS: "RSS",
+	TopByIO:  "I/O",
+}
+
+func (m TopMetric) String() string {
+	if m < 0 || m >= topMetricsNum {
+		return fmt.Sprintf("TopMetric(%d)", int(m))
+	}
+	return topMetricNames[m]
+}
+
+// Returns all metrics in display order
+func TopMetrics() []TopMetric {
+	return []TopMetric{TopByCPU, TopByRSS, TopByIO}
+}
+
+// Process taking a place in top-N by some metric
+type TopProcess struct {
+	PID        int32
+	CreateTime int64
+	Name       string
+
+	Value float64
+	// NOTE: The latest value is the first one, NaN until process is sampled
+	History []float64
+}
+
+type topProcessState struct {
+	// NOTE: CPU percent is calculated between calls on the same instance
+	proc *process.Process
+	name string
+
+	counters *Counters
+
+	values  [topMetricsNum]float64
+	history [topMetricsNum]*EntryData
+}
+
+func newTopProcessState(proc *process.Process, size int) *topProcessState {
+	state := topProcessState{proc: proc, counters: NewCounters()}
+	state.name, _ = proc.Name()
+	gap := slices.Repeat([]float64{math.NaN()}, size)
+	for m := range state.history {
+		state.history[m] = NewEntryData(size)
+		state.history[m].AddValues(gap...)
+	}
+	return &state
+}
+
+// Returns IO rate in bytes per second, NaN on the first sample or when
+// counters are not readable, e.g. for processes of other users
+func (s *topProcessState) sampleIO(now time.Time) float64 {
+	counters, err := s.proc.IOCounters()
+	if err != nil {
+		return math.NaN()
+	}
+	total := counters.ReadBytes + counters.WriteBytes
+	return s.counters.Rate(TopByIO, float64(total), now, 1)
+}
+
+func (s *topProcessState) sample(now time.Time) error {
+	perc, err := s.proc.Percent(0)
+	if err != nil {
+		return err
+	}
+	mem, err := s.proc.MemoryInfo()
+	if err != nil {
+		return err
+	}
+	s.values[TopByCPU] = perc
+	s.values[TopByRSS] = float64(mem.RSS)
+	s.values[TopByIO] = s.sampleIO(now)
+	for m, value := range s.values {
+		s.history[m].AddValues(value)
+	}
+	return nil
+}
+
+// Samples all processes each tick and keeps top-N of them by CPU percent,
+// RSS and IO rate. Collection is active while Count has subscribers
+type TopProcessesCollector struct {
+	Collector
+
+	N int
+
+	listProcesses func() ([]*process.Process, error)
+
+	procs map[processKey]*topProcessState
+
+	lock sync.Mutex
+	top  [topMetricsNum][]TopProcess
+
+	Count Entry `json:"count"`
+}
+
+// Returns collector keeping n processes per metric with history of size
+// samples each
+func NewTopProcessesCollector(n int, size int) *TopProcessesCollector {
+	if size < 1 {
+		panic("size must be greater than zero")
+	}
+	if n < 1 {
+		panic("n must be greater than zero")
+	}
+	collector := TopProcessesCollector{
+		Collector: Collector{size: size},
+
+		N: n,
+
+		listProcesses: process.Processes,
+
+		procs: make(map[processKey]*topProcessState),
+
+		Count: *NewEntry(size),
+	}
+	return &collector
+}
+
+// Returns current top by metric, the largest value is the first. Returned
+// rows must not be modified
+func (c *TopProcessesCollector) GetTop(metric TopMetric) []TopProcess {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.top[metric]
+}
+
+// Returns n processes with the largest values of metric. Processes without
+// value are skipped
+func rankTop(
+	procs map[processKey]*topProcessState, metric TopMetric, n int,
+) []TopProcess {
+	top := make([]TopProcess, 0, len(procs))
+	for key, state := range procs {
+		value := state.values[metric]
+		if math.IsNaN(value) {
+			continue
+		}
+		top = append(top, TopProcess{
+			PID:        key.pid,
+			CreateTime: key.createTime,
+			Name:       state.name,
+			Value:      value,
+			History:    state.history[metric].GetValues(),
+		})
+	}
+	slices.SortFunc(top, func(a, b TopProcess) int {
+		// NOTE: Equal values are ordered by PID to keep rows stable
+		return cmp.Or(cmp.Compare(b.Value, a.Value), cmp.Compare(a.PID, b.PID))
+	})
+	top = slices.Clip(top[:min(n, len(top))])
+	// NOTE: History is updated in place on the next tick
+	for x := range top {
+		top[x].History = slices.Clone(top[x].History)
+	}
+	return top
+}
+
+func (c *TopProcessesCollector) Collect() error {
+	if !HasActiveEntries(c) {
+		if len(c.procs) > 0 {
+			c.procs = make(map[processKey]*topProcessState)
+		}
+		return nil
+	}
+
+	procs, err := c.listProcesses()
+	if err != nil {
+		return fmt.Errorf("failed to list processes: %w", err)
+	}
+
+	now := time.Now()
+	cache := make(map[processKey]*topProcessState, len(procs))
+	for _, proc := range procs {
+		// NOTE: Processes may exit while we iterate, errors are expected
+		createTime, err := proc.CreateTime()
+		if err != nil {
+			continue
+		}
+		key := processKey{proc.Pid, createTime}
+		state, present := c.procs[key]
+		if !present {
+			state = newTopProcessState(proc, c.size)
+		}
+		if state.sample(now) != nil {
+			continue
+		}
+		cache[key] = state
+	}
+	c.procs = cache
+
+	var top [topMetricsNum][]TopProcess
+	for _, metric := range TopMetrics() {
+		top[metric] = rankTop(cache, metric, c.N)
+	}
+	c.lock.Lock()
+	c.top = top
+	c.lock.Unlock()
+
+	MapValues([]valToEntry{
+		{float64(len(cache)), &c.Count},
+	})
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package series
+
+import (
+	"math"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRankTop(t *testing.T) {
+	newState := func(name string, cpu, rss, io float64) *topProcessState {
+		state := topProcessState{name: name}
+		state.values = [topMetricsNum]float64{cpu, rss, io}
+		for m, value := range state.values {
+			state.history[m] = NewEntryData(2)
+			state.history[m].AddValues(value)
+		}
+		return &state
+	}
+	procs := map[processKey]*topProcessState{
+		{1, 10}: newState("a", 5, 300, math.NaN()),
+		{2, 20}: newState("b", 50, 100, 10),
+		{3, 30}: newState("c", 5, 200, 20),
+	}
+
+	tests := []struct {
+		name   string
+		metric TopMetric
+		n      int
+		want   []int32
+	}{
+		{"CPU ties ordered by PID", TopByCPU, 3, []int32{2, 1, 3}},
+		{"RSS", TopByRSS, 2, []int32{1, 3}},
+		{"IO skips NaN", TopByIO, 3, []int32{3, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			top := rankTop(procs, tt.metric, tt.n)
+			pids := make([]int32, 0, len(top))
+			for _, p := range top {
+				pids = append(pids, p.PID)
+				assert.Equal(t, p.Value, p.History[0])
+			}
+			assert.Equal(t, tt.want, pids)
+		})
+	}
+
+	top := rankTop(procs, TopByRSS, 1)
+	procs[processKey{1, 10}].history[TopByRSS].AddValues(1)
+	assert.Equal(t, 300.0, top[0].History[0], "history must be a copy")
+}
+
+func TestTopProcessesCollector_Collect(t *testing.T) {
+	c := NewTopProcessesCollector(3, 4)
+	require.NoError(t, c.Collect())
+	assert.Empty(t, c.GetTop(TopByRSS), "inactive collector must not collect")
+
+	sub := &Subscriber{}
+	count := c.Count.Subscribe(sub)
+	require.NoError(t, c.Collect())
+	require.NoError(t, c.Collect())
+
+	assert.Greater(t, count.GetFirstValue(), 0.0)
+	top := c.GetTop(TopByRSS)
+	require.Len(t, top, 3)
+	for x, p := range top {
+		assert.Greater(t, p.Value, 0.0)
+		assert.Equal(t, p.Value, p.History[0])
+		assert.Len(t, p.History, 4)
+		assert.True(t, math.IsNaN(p.History[3]))
+		if x > 0 {
+			assert.GreaterOrEqual(t, top[x-1].Value, p.Value)
+		}
+	}
+
+	self := false
+	for key := range c.procs {
+		self = self || key.pid == int32(os.Getpid())
+	}
+	assert.True(t, self, "current process must be sampled")
+}
@@ -0,0 +1,102 @@
+package series
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+const (
+	// Time span of samples trend is estimated from
+	DefaultTrendWindow = 5 * time.Minute
+
+	// Samples kept by trend estimator. Samples closer than window divided by
+	// this are skipped, so kept ones span the whole window
+	trendSamples = 60
+	// Trend is not estimated from fewer samples
+	minTrendSamples = 5
+)
+
+// Estimates when a falling value reaches zero, i.e. free space of disk. Slope
+// is Theil-Sen estimate, median of slopes between pairs of samples, so spikes
+// like a temporary file written and deleted don't skew it
+type TrendEstimator struct {
+	window time.Duration
+
+	times  []time.Time
+	values []float64
+	// The latest value added, may be skipped from samples
+	latest float64
+}
+
+func NewTrendEstimator(window time.Duration) *TrendEstimator {
+	if window <= 0 {
+		panic("window must be greater than zero")
+	}
+	return &TrendEstimator{
+		window: window,
+		latest: math.NaN(),
+	}
+}
+
+// Adds value taken at time at. Gaps are ignored, samples older than window
+// are dropped
+func (t *TrendEstimator) Add(at time.Time, val float64) {
+	if math.IsNaN(val) {
+		return
+	}
+	t.latest = val
+
+	count := len(t.times)
+	if count > 0 && at.Sub(t.times[count-1]) < t.window/trendSamples {
+		return
+	}
+	old := 0
+	for old < count && at.Sub(t.times[old]) > t.window {
+		old++
+	}
+	t.times = append(slices.Delete(t.times, 0, old), at)
+	t.values = append(slices.Delete(t.values, 0, old), val)
+}
+
+// Returns change of value per second, false if there are too few samples
+func (t *TrendEstimator) Slope() (slope float64, valid bool) {
+	count := len(t.times)
+	if count < minTrendSamples {
+		return 0, false
+	}
+	slopes := make([]float64, 0, count*(count-1)/2)
+	for i := 0; i < count; i++ {
+		for j := i + 1; j < count; j++ {
+			dt := t.times[j].Sub(t.times[i]).Seconds()
+			if dt > 0 {
+				slopes = append(slopes, (t.values[j]-t.values[i])/dt)
+			}
+		}
+	}
+	if len(slopes) == 0 {
+		return 0, false
+	}
+	slices.Sort(slopes)
+	mid := len(slopes) / 2
+	if len(slopes)%2 == 0 {
+		return (slopes[mid-1] + slopes[mid]) / 2, true
+	}
+	return slopes[mid], true
+}
+
+// Returns seconds until the latest value reaches zero at estimated slope.
+// +Inf if value doesn't fall, NaN if trend is not known yet
+func (t *TrendEstimator) TimeToZero() float64 {
+	slope, valid := t.Slope()
+	if !valid || math.IsNaN(t.latest) {
+		return math.NaN()
+	}
+	if t.latest <= 0 {
+		return 0
+	}
+	if slope >= 0 {
+		return math.Inf(1)
+	}
+	return t.latest / -slope
+}
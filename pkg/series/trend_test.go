@@ -0,0 +1,130 @@
+package series
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns value of fill pattern at sample x
+type fillPattern func(x int) float64
+
+func TestTrendEstimator_TimeToZero(t *testing.T) {
+	const gib = 1 << 30
+	interval := 10 * time.Second
+
+	tests := []struct {
+		name    string
+		samples int
+		fill    fillPattern
+		// Seconds, NaN when trend is not known
+		want  float64
+		delta float64
+	}{
+		{
+			name:    "Too few samples",
+			samples: minTrendSamples - 1,
+			fill:    func(x int) float64 { return 10*gib - float64(x)*gib },
+			want:    math.NaN(),
+		},
+		{
+			name:    "Steady",
+			samples: 30,
+			fill:    func(int) float64 { return 10 * gib },
+			want:    math.Inf(1),
+		},
+		{
+			name:    "Freed",
+			samples: 30,
+			fill:    func(x int) float64 { return gib + float64(x)*gib },
+			want:    math.Inf(1),
+		},
+		{
+			// 1 MiB per second, 100 MiB left after the last sample
+			name:    "Linear fill",
+			samples: 30,
+			fill:    func(x int) float64 { return float64(100+10*(29-x)) * (1 << 20) },
+			want:    100,
+			delta:   0.001,
+		},
+		{
+			name:    "Linear fill with noise",
+			samples: 30,
+			fill: func(x int) float64 {
+				noise := float64(x%3-1) * (1 << 20)
+				return float64(100+10*(29-x))*(1<<20) + noise
+			},
+			want:  100,
+			delta: 20,
+		},
+		{
+			// Temporary files written and deleted don't change the trend
+			name:    "Linear fill with spikes",
+			samples: 30,
+			fill: func(x int) float64 {
+				val := float64(100+10*(29-x)) * (1 << 20)
+				if x%7 == 3 {
+					val -= 5 * gib
+				}
+				return val
+			},
+			want:  100,
+			delta: 0.001,
+		},
+		{
+			name:    "Full",
+			samples: 30,
+			fill:    func(x int) float64 { return float64(max(0, 20-x)) * gib },
+			want:    0,
+		},
+		{
+			// Samples older than window don't count, fill stopped 5 minutes
+			// ago
+			name:    "Fill before window",
+			samples: 90,
+			fill: func(x int) float64 {
+				return float64(max(100, 150-x)) * gib
+			},
+			want: math.Inf(1),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trend := NewTrendEstimator(DefaultTrendWindow)
+			start := time.Unix(0, 0)
+			for x := range tt.samples {
+				trend.Add(start.Add(time.Duration(x)*interval), tt.fill(x))
+			}
+			got := trend.TimeToZero()
+			if math.IsNaN(tt.want) || math.IsInf(tt.want, 0) {
+				assert.Equal(t, math.IsNaN(tt.want), math.IsNaN(got), got)
+				assert.Equal(t, math.IsInf(tt.want, 1), math.IsInf(got, 1), got)
+				return
+			}
+			assert.InDelta(t, tt.want, got, tt.delta)
+		})
+	}
+}
+
+func TestTrendEstimator_Add(t *testing.T) {
+	trend := NewTrendEstimator(time.Minute)
+	start := time.Unix(0, 0)
+
+	// Samples closer than window/trendSamples update the latest value only
+	for x := range 100 {
+		trend.Add(start.Add(time.Duration(x)*100*time.Millisecond), float64(100-x))
+	}
+	assert.Len(t, trend.times, 10)
+	assert.Equal(t, 1.0, trend.latest)
+
+	// Gaps are ignored
+	trend.Add(start.Add(20*time.Second), math.NaN())
+	assert.Len(t, trend.times, 10)
+	assert.Equal(t, 1.0, trend.latest)
+
+	// Samples older than window are dropped
+	trend.Add(start.Add(65*time.Second), 1)
+	assert.Len(t, trend.times, 6)
+}
@@ -0,0 +1,30 @@
+package series
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Parses data in /proc/vmstat format, lines of name and counter
+func parseVmstat(r io.Reader) (map[string]uint64, error) {
+	values := make(map[string]uint64)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		num, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse vmstat %s: %w", fields[0], err)
+		}
+		values[fields[0]] = num
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read vmstat: %w", err)
+	}
+	return values, nil
+}
@@ -0,0 +1,102 @@
+package tickstore
+
+import (
+	"math"
+	"slices"
+)
+
+// Statistics of the last values pushed into a window of fixed size. Values
+// are kept sorted, so push costs a binary search and a shift of the window
+// instead of recalculating stats over all data. NaN values are gaps, they
+// take place in the window but are not counted
+type RollingStats struct {
+	// Ring of window values in push order, pos is the oldest one
+	window []float64
+	pos    int
+	filled int
+
+	sorted []float64
+	sum    float64
+}
+
+func NewRollingStats(size int) *RollingStats {
+	if size < 1 {
+		panic("size must be greater than zero")
+	}
+	return &RollingStats{
+		window: make([]float64, size),
+		sorted: make([]float64, 0, size),
+	}
+}
+
+func (rs *RollingStats) GetSize() int {
+	return len(rs.window)
+}
+
+// Adds value to the window, the oldest value leaves it when it's full
+func (rs *RollingStats) Push(val float64) {
+	if rs.filled == len(rs.window) {
+		rs.remove(rs.window[rs.pos])
+	} else {
+		rs.filled++
+	}
+	rs.window[rs.pos] = val
+	rs.pos = (rs.pos + 1) % len(rs.window)
+
+	if math.IsNaN(val) || math.IsInf(val, 0) {
+		return
+	}
+	idx, _ := slices.BinarySearch(rs.sorted, val)
+	rs.sorted = slices.Insert(rs.sorted, idx, val)
+	rs.sum += val
+}
+
+func (rs *RollingStats) remove(val float64) {
+	if math.IsNaN(val) || math.IsInf(val, 0) {
+		return
+	}
+	idx, found := slices.BinarySearch(rs.sorted, val)
+	if !found {
+		return
+	}
+	rs.sorted = slices.Delete(rs.sorted, idx, idx+1)
+	rs.sum -= val
+}
+
+func (rs *RollingStats) Reset() {
+	rs.pos, rs.filled = 0, 0
+	rs.sorted = rs.sorted[:0]
+	rs.sum = 0
+}
+
+// Returns mean of window values, NaN if there are none
+func (rs *RollingStats) Mean() float64 {
+	if len(rs.sorted) == 0 {
+		return math.NaN()
+	}
+	return rs.sum / float64(len(rs.sorted))
+}
+
+// Returns nearest rank quantile of window values, i.e. 0.95 for p95. NaN if
+// there are none
+func (rs *RollingStats) Quantile(q float64) float64 {
+	if len(rs.sorted) == 0 {
+		return math.NaN()
+	}
+	rank := int(math.Ceil(q * float64(len(rs.sorted))))
+	return rs.sorted[max(min(rank, len(rs.sorted)), 1)-1]
+}
+
+func (rs *RollingStats) Min() float64 {
+	if len(rs.sorted) == 0 {
+		return math.NaN()
+	}
+	return rs.sorted[0]
+}
+
+func (rs *RollingStats) Max() float64 {
+	if len(rs.sorted) == 0 {
+		return math.NaN()
+	}
+	return rs.sorted[len(rs.sorted)-1]
+}
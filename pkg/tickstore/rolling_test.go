@@ -0,0 +1,107 @@
+package tickstore
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRollingStats(t *testing.T) {
+	assert.Panics(t, func() { NewRollingStats(0) })
+	assert.Equal(t, 3, NewRollingStats(3).GetSize())
+}
+
+func TestRollingStats(t *testing.T) {
+	nan := math.NaN()
+	tests := []struct {
+		name   string
+		size   int
+		values []float64
+		// Stats after all values are pushed
+		wantMean, wantP95, wantMin, wantMax float64
+	}{
+		{
+			name:     "Empty",
+			size:     3,
+			wantMean: nan, wantP95: nan, wantMin: nan, wantMax: nan,
+		},
+		{
+			name:     "Partial window",
+			size:     4,
+			values:   []float64{3, 1, 2},
+			wantMean: 2, wantP95: 3, wantMin: 1, wantMax: 3,
+		},
+		{
+			name:     "Old values leave window",
+			size:     3,
+			values:   []float64{100, -5, 4, 6, 2},
+			wantMean: 4, wantP95: 6, wantMin: 2, wantMax: 6,
+		},
+		{
+			name:     "Gaps are not counted",
+			size:     3,
+			values:   []float64{1, 7, nan, 3},
+			wantMean: 5, wantP95: 7, wantMin: 3, wantMax: 7,
+		},
+		{
+			name:     "Window of gaps",
+			size:     2,
+			values:   []float64{1, nan, math.Inf(1)},
+			wantMean: nan, wantP95: nan, wantMin: nan, wantMax: nan,
+		},
+		{
+			name:     "Repeated values",
+			size:     3,
+			values:   []float64{5, 5, 1, 5},
+			wantMean: 11.0 / 3, wantP95: 5, wantMin: 1, wantMax: 5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rs := NewRollingStats(tt.size)
+			for _, val := range tt.values {
+				rs.Push(val)
+			}
+			assertFloat(t, tt.wantMean, rs.Mean(), "mean")
+			assertFloat(t, tt.wantP95, rs.Quantile(0.95), "p95")
+			assertFloat(t, tt.wantMin, rs.Min(), "min")
+			assertFloat(t, tt.wantMax, rs.Max(), "max")
+		})
+	}
+}
+
+func TestRollingStats_Quantile(t *testing.T) {
+	rs := NewRollingStats(100)
+	for x := range 100 {
+		rs.Push(float64(100 - x))
+	}
+	assert.Equal(t, 95.0, rs.Quantile(0.95))
+	assert.Equal(t, 50.0, rs.Quantile(0.5))
+	assert.Equal(t, 1.0, rs.Quantile(0))
+	assert.Equal(t, 100.0, rs.Quantile(1))
+}
+
+func TestRollingStats_Reset(t *testing.T) {
+	rs := NewRollingStats(2)
+	rs.Push(10)
+	rs.Push(20)
+	rs.Reset()
+	assert.True(t, math.IsNaN(rs.Mean()))
+
+	rs.Push(4)
+	rs.Push(6)
+	rs.Push(8)
+	assert.Equal(t, 7.0, rs.Mean())
+	assert.True(t, slices.IsSorted(rs.sorted))
+}
+
+func assertFloat(t *testing.T, want, got float64, msg string) {
+	t.Helper()
+	if math.IsNaN(want) {
+		assert.True(t, math.IsNaN(got), "%s: %v", msg, got)
+		return
+	}
+	assert.InDelta(t, want, got, 1e-9, msg)
+}